@@ -0,0 +1,324 @@
+package ctxd
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy defines behavior of AsyncLogger when queue is full.
+type OverflowPolicy int
+
+// Overflow policies.
+const (
+	// OverflowBlock blocks caller until queue has space.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropNewest drops the entry being logged.
+	OverflowDropNewest
+
+	// OverflowDropOldest drops the oldest entry in queue to make space for the entry being logged.
+	OverflowDropOldest
+
+	// OverflowDropBelowLevel drops the entry being logged if its level is below AsyncConfig.DropBelow,
+	// and blocks otherwise. Important messages are never dropped.
+	OverflowDropBelowLevel
+)
+
+// ErrLoggerClosed is returned by AsyncLogger.Flush after logger is closed.
+const ErrLoggerClosed = SentinelError("logger closed")
+
+// AsyncConfig defines AsyncLogger behavior.
+type AsyncConfig struct {
+	// QueueSize is a capacity of entries queue, default 1024.
+	QueueSize int
+
+	// Overflow defines behavior when queue is full, default OverflowBlock.
+	Overflow OverflowPolicy
+
+	// DropBelow is a minimal level of entries that are not dropped with OverflowDropBelowLevel.
+	DropBelow Level
+}
+
+// AsyncLogger writes entries to underlying logger in a background goroutine.
+//
+// Context of a call is detached from cancellation, so that values (including fields) remain available
+// when entry is written after the call has returned.
+type AsyncLogger struct {
+	dropped uint64
+
+	logger  Logger
+	cfg     AsyncConfig
+	queue   chan asyncEntry
+	done    chan struct{}
+	closing chan struct{}
+
+	// senders tracks calls that may send to queue, queue is closed after they finish.
+	senders sync.WaitGroup
+	mu      sync.Mutex
+	closed  bool
+}
+
+var _ Logger = &AsyncLogger{}
+
+type asyncMethod int
+
+const (
+	asyncDebug asyncMethod = iota
+	asyncInfo
+	asyncImportant
+	asyncWarn
+	asyncError
+	asyncFlush
+)
+
+type asyncEntry struct {
+	method        asyncMethod
+	ctx           context.Context //nolint:containedctx // Detached context is stored for deferred logging.
+	msg           string
+	keysAndValues []interface{}
+	flushed       chan struct{}
+
+	// flushes are markers of evicted Flush calls, they are completed after entry is written.
+	flushes []chan struct{}
+}
+
+// NewAsyncLogger creates asynchronous logger and starts background goroutine.
+//
+// Close should be called to flush queued entries and stop background goroutine.
+func NewAsyncLogger(logger Logger, cfg AsyncConfig) *AsyncLogger {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+
+	l := &AsyncLogger{
+		logger:  logger,
+		cfg:     cfg,
+		queue:   make(chan asyncEntry, cfg.QueueSize),
+		done:    make(chan struct{}),
+		closing: make(chan struct{}),
+	}
+
+	go l.run()
+
+	return l
+}
+
+func (l *AsyncLogger) run() {
+	defer close(l.done)
+
+	for e := range l.queue {
+		switch e.method {
+		case asyncDebug:
+			l.logger.Debug(e.ctx, e.msg, e.keysAndValues...)
+		case asyncInfo:
+			l.logger.Info(e.ctx, e.msg, e.keysAndValues...)
+		case asyncImportant:
+			l.logger.Important(e.ctx, e.msg, e.keysAndValues...)
+		case asyncWarn:
+			l.logger.Warn(e.ctx, e.msg, e.keysAndValues...)
+		case asyncError:
+			l.logger.Error(e.ctx, e.msg, e.keysAndValues...)
+		case asyncFlush:
+			close(e.flushed)
+		}
+
+		for _, f := range e.flushes {
+			close(f)
+		}
+	}
+}
+
+// Enabled checks if underlying logger is enabled for level in context.
+func (l *AsyncLogger) Enabled(ctx context.Context, level Level) bool {
+	return Enabled(ctx, l.logger, level)
+}
+
+// Debug queues a message.
+func (l *AsyncLogger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.enqueue(ctx, asyncDebug, DebugLevel, msg, keysAndValues)
+}
+
+// Info queues a message.
+func (l *AsyncLogger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.enqueue(ctx, asyncInfo, InfoLevel, msg, keysAndValues)
+}
+
+// Important queues a message.
+func (l *AsyncLogger) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.enqueue(ctx, asyncImportant, InfoLevel, msg, keysAndValues)
+}
+
+// Warn queues a message.
+func (l *AsyncLogger) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.enqueue(ctx, asyncWarn, WarnLevel, msg, keysAndValues)
+}
+
+// Error queues a message.
+func (l *AsyncLogger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.enqueue(ctx, asyncError, ErrorLevel, msg, keysAndValues)
+}
+
+// Dropped returns number of entries dropped due to queue overflow or closed logger.
+func (l *AsyncLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// acquire registers a sender, it returns false if logger is closed.
+func (l *AsyncLogger) acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return false
+	}
+
+	l.senders.Add(1)
+
+	return true
+}
+
+func (l *AsyncLogger) enqueue(ctx context.Context, method asyncMethod, level Level, msg string, keysAndValues []interface{}) {
+	if !l.acquire() {
+		atomic.AddUint64(&l.dropped, 1)
+
+		return
+	}
+
+	defer l.senders.Done()
+
+	e := asyncEntry{
+		method:        method,
+		ctx:           detachedContext{parent: ctx},
+		msg:           msg,
+		keysAndValues: append([]interface{}(nil), keysAndValues...),
+	}
+
+	policy := l.cfg.Overflow
+	if policy == OverflowDropBelowLevel && (method == asyncImportant || level >= l.cfg.DropBelow) {
+		policy = OverflowBlock
+	}
+
+	switch policy {
+	case OverflowBlock:
+		select {
+		case l.queue <- e:
+			return
+		default:
+		}
+
+		// Blocked entry is dropped when logger is closing to avoid stalling Close.
+		select {
+		case l.queue <- e:
+		case <-l.closing:
+			atomic.AddUint64(&l.dropped, 1)
+		}
+	case OverflowDropNewest, OverflowDropBelowLevel:
+		select {
+		case l.queue <- e:
+		default:
+			atomic.AddUint64(&l.dropped, 1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case l.queue <- e:
+				return
+			default:
+			}
+
+			select {
+			case old := <-l.queue:
+				// Flush markers are not dropped to avoid blocking Flush, they are moved to the new entry
+				// to be completed after preceding entries are written.
+				if old.method == asyncFlush {
+					e.flushes = append(e.flushes, old.flushed)
+				} else {
+					atomic.AddUint64(&l.dropped, 1)
+				}
+
+				e.flushes = append(e.flushes, old.flushes...)
+			default:
+			}
+		}
+	}
+}
+
+// Flush waits until entries queued before the call are written to underlying logger.
+func (l *AsyncLogger) Flush(ctx context.Context) error {
+	if !l.acquire() {
+		return ErrLoggerClosed
+	}
+
+	flushed := make(chan struct{})
+
+	select {
+	case l.queue <- asyncEntry{method: asyncFlush, flushed: flushed}:
+		l.senders.Done()
+	case <-l.closing:
+		l.senders.Done()
+
+		return ErrLoggerClosed
+	case <-ctx.Done():
+		l.senders.Done()
+
+		return ctx.Err()
+	}
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting new entries and waits until queued entries are written to underlying logger.
+//
+// Entries logged after Close and entries blocked on a full queue are dropped.
+// Close returns ctx error if underlying logger does not catch up before ctx is done.
+func (l *AsyncLogger) Close(ctx context.Context) error {
+	l.mu.Lock()
+
+	if !l.closed {
+		l.closed = true
+		close(l.closing)
+
+		go func() {
+			// Queue is closed when no more entries can be sent to it.
+			l.senders.Wait()
+			close(l.queue)
+		}()
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// detachedContext keeps values of parent context, but discards its deadline and cancellation.
+type detachedContext struct {
+	parent context.Context //nolint:containedctx // Parent context is a source of values.
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
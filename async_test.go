@@ -0,0 +1,225 @@
+package ctxd_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedLogger blocks on every Info call until released.
+type gatedLogger struct {
+	ctxd.LoggerMock
+
+	started chan struct{}
+	release chan struct{}
+}
+
+func newGatedLogger() *gatedLogger {
+	return &gatedLogger{
+		started: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (g *gatedLogger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	g.started <- struct{}{}
+	<-g.release
+	g.LoggerMock.Info(ctx, msg, keysAndValues...)
+}
+
+func TestAsyncLogger(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxd.NewAsyncLogger(&lm, ctxd.AsyncConfig{})
+
+	ctx, cancel := context.WithCancel(ctxd.AddFields(context.Background(), "foo", 1))
+
+	l.Debug(ctx, "debug", "a", 1)
+	l.Info(ctx, "info", "a", 1)
+	l.Important(ctx, "important", "a", 1)
+	l.Warn(ctx, "warn", "a", 1)
+	l.Error(ctx, "error", "a", 1)
+	cancel()
+
+	require.NoError(t, l.Flush(context.Background()))
+	assert.Equal(t, `debug: debug {"a":1,"foo":1}
+info: info {"a":1,"foo":1}
+important: important {"a":1,"foo":1}
+warn: warn {"a":1,"foo":1}
+error: error {"a":1,"foo":1}
+`, lm.String())
+
+	require.NoError(t, l.Close(context.Background()))
+	require.NoError(t, l.Close(context.Background()))
+
+	l.Info(ctx, "after close")
+	assert.Equal(t, uint64(1), l.Dropped())
+	assert.True(t, errors.Is(l.Flush(context.Background()), ctxd.ErrLoggerClosed))
+}
+
+func TestAsyncLogger_overflow(t *testing.T) {
+	for _, tc := range []struct {
+		policy   ctxd.OverflowPolicy
+		expected string
+	}{
+		{
+			policy:   ctxd.OverflowDropNewest,
+			expected: "info: 1 null\ninfo: 2 null\ninfo: 3 null\n",
+		},
+		{
+			policy:   ctxd.OverflowDropOldest,
+			expected: "info: 1 null\ninfo: 3 null\ninfo: 4 null\n",
+		},
+		{
+			policy:   ctxd.OverflowDropBelowLevel,
+			expected: "info: 1 null\ninfo: 2 null\ninfo: 3 null\n",
+		},
+	} {
+		g := newGatedLogger()
+		l := ctxd.NewAsyncLogger(g, ctxd.AsyncConfig{
+			QueueSize: 2,
+			Overflow:  tc.policy,
+			DropBelow: ctxd.WarnLevel,
+		})
+		ctx := context.Background()
+
+		l.Info(ctx, "1")
+		<-g.started // Worker is blocked with first entry.
+
+		l.Info(ctx, "2")
+		l.Info(ctx, "3")
+		l.Info(ctx, "4") // Queue is full.
+
+		close(g.release)
+		require.NoError(t, l.Close(ctx))
+
+		assert.Equal(t, tc.expected, g.String())
+		assert.Equal(t, uint64(1), l.Dropped())
+	}
+}
+
+func TestAsyncLogger_Flush_canceled(t *testing.T) {
+	g := newGatedLogger()
+	l := ctxd.NewAsyncLogger(g, ctxd.AsyncConfig{QueueSize: 1})
+
+	l.Info(context.Background(), "1")
+	<-g.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, context.Canceled, l.Flush(ctx))
+	assert.Equal(t, context.Canceled, l.Close(ctx))
+
+	close(g.release)
+}
+
+func TestAsyncLogger_concurrent(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxd.NewAsyncLogger(&lm, ctxd.AsyncConfig{QueueSize: 10, Overflow: ctxd.OverflowDropOldest})
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				l.Info(context.Background(), "hello")
+			}
+
+			assert.NoError(t, l.Flush(context.Background()))
+		}()
+	}
+
+	wg.Wait()
+	require.NoError(t, l.Close(context.Background()))
+
+	assert.Equal(t, 1000, len(lm.LoggedEntries)+int(l.Dropped()))
+}
+
+func TestAsyncLogger_Close_deadline(t *testing.T) {
+	g := newGatedLogger()
+	l := ctxd.NewAsyncLogger(g, ctxd.AsyncConfig{QueueSize: 1})
+	ctx := context.Background()
+
+	l.Info(ctx, "1")
+	<-g.started
+
+	l.Info(ctx, "2") // Fills the queue.
+
+	blocked := make(chan struct{})
+
+	go func() {
+		defer close(blocked)
+
+		l.Info(ctx, "3") // Blocks on full queue.
+	}()
+
+	flushed := make(chan error)
+
+	go func() {
+		flushed <- l.Flush(ctx) // Blocks on full queue.
+	}()
+
+	dctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	assert.Equal(t, context.DeadlineExceeded, l.Close(dctx))
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Blocked calls are released by Close.
+	<-blocked
+	assert.Equal(t, ctxd.ErrLoggerClosed, <-flushed)
+
+	dctx, cancel = context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, ctxd.ErrLoggerClosed, l.Flush(dctx))
+
+	close(g.release)
+
+	require.NoError(t, l.Close(ctx))
+	assert.Equal(t, "info: 1 null\ninfo: 2 null\n", g.String())
+	assert.Equal(t, uint64(1), l.Dropped())
+}
+
+func TestAsyncLogger_Flush_dropOldest(t *testing.T) {
+	g := newGatedLogger()
+	l := ctxd.NewAsyncLogger(g, ctxd.AsyncConfig{QueueSize: 1, Overflow: ctxd.OverflowDropOldest})
+	ctx := context.Background()
+
+	l.Info(ctx, "1")
+	<-g.started // Worker is writing first entry.
+
+	flushed := make(chan error)
+
+	go func() {
+		flushed <- l.Flush(ctx) // Flush marker fills the queue.
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	l.Info(ctx, "2") // Evicts flush marker.
+
+	// Flush is not completed before first entry is written.
+	select {
+	case err := <-flushed:
+		t.Fatalf("unexpected flush before entries are written: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(g.release)
+
+	require.NoError(t, <-flushed)
+	assert.Equal(t, "info: 1 null\ninfo: 2 null\n", g.String())
+	require.NoError(t, l.Close(ctx))
+}
@@ -0,0 +1,175 @@
+package ctxd
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// ctxdPackage is an import path of this package, e.g. "github.com/bool64/ctxd".
+var ctxdPackage = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name() // E.g. "github.com/bool64/ctxd.glob..func1".
+
+	lastSlash := strings.LastIndex(name, "/")
+
+	return name[:lastSlash+strings.Index(name[lastSlash:], ".")]
+}()
+
+// inPackage checks if function belongs to package or its subpackages.
+func inPackage(function, pkg string) bool {
+	if !strings.HasPrefix(function, pkg) || len(function) == len(pkg) {
+		return false
+	}
+
+	c := function[len(pkg)]
+
+	return c == '.' || c == '/'
+}
+
+// callerFrame returns first frame of call stack outside of ctxd and skipped packages.
+func callerFrame(skipPackages []string) (runtime.Frame, bool) {
+	var pcs [32]uintptr
+
+	// Skip runtime.Callers and callerFrame.
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		f, more := frames.Next()
+
+		if !skipFrame(f.Function, skipPackages) {
+			return f, true
+		}
+
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+func skipFrame(function string, skipPackages []string) bool {
+	if inPackage(function, ctxdPackage) {
+		return true
+	}
+
+	for _, p := range skipPackages {
+		if inPackage(function, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trimmedPath returns file name with parent directory, e.g. "ctxd/caller.go".
+func trimmedPath(file string) string {
+	i := strings.LastIndexByte(file, '/')
+	if i == -1 {
+		return file
+	}
+
+	if j := strings.LastIndexByte(file[:i], '/'); j != -1 {
+		return file[j+1:]
+	}
+
+	return file
+}
+
+func appendCaller(kv []interface{}, names FieldNames, f runtime.Frame) []interface{} {
+	return append(kv,
+		names.CallerFile, trimmedPath(f.File),
+		names.CallerLine, f.Line,
+		names.CallerFunction, f.Function,
+	)
+}
+
+var errorCallerEnabled int32
+
+// CaptureErrorCaller enables or disables adding caller location fields to errors created with NewError and WrapError.
+//
+// Caller fields use default FieldNames and are not added when wrapped error already has them.
+func CaptureErrorCaller(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&errorCallerEnabled, v)
+}
+
+// errorCaller returns caller fields if error caller capture is enabled and tuples don't have them yet.
+func errorCaller(tuples []interface{}) []interface{} {
+	if atomic.LoadInt32(&errorCallerEnabled) == 0 {
+		return nil
+	}
+
+	for i := 0; i < len(tuples); i += 2 {
+		if tuples[i] == defaultFieldNames.CallerFile {
+			return nil
+		}
+	}
+
+	f, ok := callerFrame(nil)
+	if !ok {
+		return nil
+	}
+
+	return appendCaller(make([]interface{}, 0, 6), defaultFieldNames, f)
+}
+
+// LoggerWithCaller instruments contextualized logger with caller location fields.
+//
+// Caller is the first frame of call stack outside of ctxd packages and skipPackages,
+// so that wrappers (e.g. LoggerWithFields) and helpers (e.g. LogError) are not reported.
+// Packages of third-party wrappers should be added to skipPackages, e.g. "log/slog".
+//
+// Empty field names are replaced with defaults.
+// Caller fields of structured errors (see CaptureErrorCaller) take precedence in LogError.
+func LoggerWithCaller(logger Logger, names FieldNames, skipPackages ...string) Logger {
+	return &withCaller{
+		logger:       logger,
+		names:        names.WithDefaults(),
+		skipPackages: skipPackages,
+	}
+}
+
+type withCaller struct {
+	logger       Logger
+	names        FieldNames
+	skipPackages []string
+}
+
+func (w *withCaller) ctx(ctx context.Context) context.Context {
+	f, ok := callerFrame(w.skipPackages)
+	if !ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, fieldsCtxKey{}, appendCaller(Fields(ctx), w.names, f))
+}
+
+func (w *withCaller) Enabled(ctx context.Context, level Level) bool {
+	return Enabled(ctx, w.logger, level)
+}
+
+func (w *withCaller) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Debug(w.ctx(ctx), msg, keysAndValues...)
+}
+
+func (w *withCaller) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Info(w.ctx(ctx), msg, keysAndValues...)
+}
+
+func (w *withCaller) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Important(w.ctx(ctx), msg, keysAndValues...)
+}
+
+func (w *withCaller) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Warn(w.ctx(ctx), msg, keysAndValues...)
+}
+
+func (w *withCaller) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Error(w.ctx(ctx), msg, keysAndValues...)
+}
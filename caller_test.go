@@ -0,0 +1,70 @@
+package ctxd_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertCallerFile checks and removes file name that contains parent directory of checkout.
+func assertCallerFile(t *testing.T, data map[string]interface{}) {
+	t.Helper()
+
+	assert.True(t, strings.HasSuffix(data["log.origin.file.name"].(string), "/caller_test.go"))
+	delete(data, "log.origin.file.name")
+}
+
+func TestLoggerWithCaller(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithFields(ctxd.LoggerWithCaller(&lm, ctxd.FieldNames{CallerLine: "line"}), "foo", 1)
+	ctx := context.Background()
+
+	_, _, line, _ := runtime.Caller(0)
+	l.Info(ctx, "info")
+	ctxd.LogError(ctx, errors.New("failed"), l.Error)
+
+	require.Len(t, lm.LoggedEntries, 2)
+	assertCallerFile(t, lm.LoggedEntries[0].Data)
+	assert.Equal(t, map[string]interface{}{
+		"foo":                 1,
+		"line":                line + 1,
+		"log.origin.function": "github.com/bool64/ctxd_test.TestLoggerWithCaller",
+	}, lm.LoggedEntries[0].Data)
+	assert.Equal(t, line+2, lm.LoggedEntries[1].Data["line"])
+}
+
+func TestCaptureErrorCaller(t *testing.T) {
+	ctxd.CaptureErrorCaller(true)
+	defer ctxd.CaptureErrorCaller(false)
+
+	lm := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithCaller(&lm, ctxd.FieldNames{})
+	ctx := context.Background()
+
+	_, _, line, _ := runtime.Caller(0)
+	err := ctxd.NewError(ctx, "failed")
+	err = ctxd.WrapError(ctx, err, "wrapped")
+
+	ctxd.LogError(ctx, err, l.Error)
+
+	require.Len(t, lm.LoggedEntries, 1)
+	assertCallerFile(t, lm.LoggedEntries[0].Data)
+	assert.Equal(t, map[string]interface{}{
+		"log.origin.file.line": line + 1,
+		"log.origin.function":  "github.com/bool64/ctxd_test.TestCaptureErrorCaller",
+	}, lm.LoggedEntries[0].Data)
+
+	ctxd.CaptureErrorCaller(false)
+
+	err = ctxd.NewError(ctx, "failed")
+
+	var se ctxd.StructuredError
+
+	assert.False(t, errors.As(err, &se))
+}
@@ -1,11 +1,25 @@
 package ctxd
 
+import "reflect"
+
 // FieldNames defines standard field names.
 //
 // Default names are aligned with https://www.elastic.co/guide/en/ecs/current/ecs-field-reference.html.
 type FieldNames struct {
 	Timestamp string `default:"@timestamp"`
 	Message   string `default:"message"`
+	Level     string `default:"log.level"`
+
+	// LoggerName is a name of component logger (see Named).
+	LoggerName string `default:"log.logger"`
+
+	// CallerFile is a name of source file (with parent directory) where log entry or error originated.
+	CallerFile     string `default:"log.origin.file.name"`
+	CallerLine     string `default:"log.origin.file.line"`
+	CallerFunction string `default:"log.origin.function"`
+
+	// ErrorStackTrace is a call stack of error creation.
+	ErrorStackTrace string `default:"error.stack_trace"`
 
 	// ClientIP is an IP address of the client (IPv4 or IPv6).
 	ClientIP string `default:"client.ip"`
@@ -19,7 +33,41 @@ type FieldNames struct {
 	// UserAgentOriginal is an unparsed user_agent string.
 	UserAgentOriginal string `default:"user_agent.original"`
 
+	// EventDuration is a duration of the event, e.g. HTTP request processing.
+	EventDuration string `default:"event.duration"`
+
 	SpanID        string `default:"span.id"`
 	TraceID       string `default:"trace.id"`
 	TransactionID string `default:"transaction.id"`
 }
+
+var defaultFieldNames = func() FieldNames {
+	fn := FieldNames{}
+	v := reflect.ValueOf(&fn).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		v.Field(i).SetString(t.Field(i).Tag.Get("default"))
+	}
+
+	return fn
+}()
+
+// DefaultFieldNames returns field names with default values.
+func DefaultFieldNames() FieldNames {
+	return defaultFieldNames
+}
+
+// WithDefaults returns a copy of field names with empty values replaced by defaults.
+func (fn FieldNames) WithDefaults() FieldNames {
+	v := reflect.ValueOf(&fn).Elem()
+	d := reflect.ValueOf(defaultFieldNames)
+
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).String() == "" {
+			v.Field(i).SetString(d.Field(i).String())
+		}
+	}
+
+	return fn
+}
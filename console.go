@@ -0,0 +1,368 @@
+package ctxd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ColorMode defines colorization of console output.
+type ColorMode int
+
+// Color modes.
+const (
+	// ColorAuto enables colors if output is a terminal and NO_COLOR environment variable is not set.
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+// ConsoleLogger writes human-readable log entries, suitable for local development.
+//
+// Default format is an aligned text with timestamp, level, message and fields sorted by key,
+// alternatively logfmt can be enabled. Fields are merged with the same semantics as in JSONLogger.
+type ConsoleLogger struct {
+	// Output receives log entries, os.Stderr is used if nil.
+	// Writer from context (see WithLogWriter) takes precedence over Output.
+	Output io.Writer
+
+	// Logfmt enables logfmt format (time=... level=... msg=... key=value).
+	Logfmt bool
+
+	// Colors defines colorization of aligned text, logfmt is never colorized.
+	// Writers from context are only colorized with ColorAlways.
+	Colors ColorMode
+
+	// TimeFormat is a layout of timestamp, default "2006-01-02 15:04:05.000" for text
+	// and time.RFC3339Nano for logfmt.
+	TimeFormat string
+
+	// DebugEnabled allows Debug messages, otherwise minimal level is InfoLevel.
+	// Level from context (see WithLevel, WithDebug) takes precedence.
+	DebugEnabled bool
+
+	// OnError is called on failure to write an entry, errors are ignored if nil.
+	OnError func(err error)
+
+	// TimeNow returns current time, time.Now is used if nil.
+	TimeNow func() time.Time
+
+	once       sync.Once
+	timeFormat string
+	colored    bool
+	mu         sync.Mutex
+}
+
+var _ Logger = &ConsoleLogger{}
+
+// Enabled checks if messages of level would be logged in context.
+func (l *ConsoleLogger) Enabled(ctx context.Context, level Level) bool {
+	return level == ImportantLevel || levelEnabled(ctx, level, l.minLevel())
+}
+
+// Debug logs a message if debug is enabled in logger or in context.
+func (l *ConsoleLogger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, DebugLevel, l.minLevel()) {
+		l.log(ctx, "debug", msg, keysAndValues)
+	}
+}
+
+// Info logs a message.
+func (l *ConsoleLogger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, InfoLevel, l.minLevel()) {
+		l.log(ctx, "info", msg, keysAndValues)
+	}
+}
+
+// Important logs a message with level "info".
+func (l *ConsoleLogger) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, "info", msg, keysAndValues)
+}
+
+// Warn logs a message.
+func (l *ConsoleLogger) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, WarnLevel, l.minLevel()) {
+		l.log(ctx, "warn", msg, keysAndValues)
+	}
+}
+
+// Error logs a message.
+func (l *ConsoleLogger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, ErrorLevel, l.minLevel()) {
+		l.log(ctx, "error", msg, keysAndValues)
+	}
+}
+
+func (l *ConsoleLogger) init() {
+	l.timeFormat = l.TimeFormat
+
+	if l.timeFormat == "" {
+		if l.Logfmt {
+			l.timeFormat = time.RFC3339Nano
+		} else {
+			l.timeFormat = "2006-01-02 15:04:05.000"
+		}
+	}
+
+	switch l.Colors {
+	case ColorAlways:
+		l.colored = true
+	case ColorNever:
+		l.colored = false
+	case ColorAuto:
+		out := l.Output
+		if out == nil {
+			out = os.Stderr
+		}
+
+		_, noColor := os.LookupEnv("NO_COLOR")
+		l.colored = !noColor && isTerminal(out)
+	}
+}
+
+// isTerminal checks if writer is a character device.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return st.Mode()&os.ModeCharDevice != 0
+}
+
+const (
+	colorReset  = "\x1b[0m"
+	colorGray   = "\x1b[90m"
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[34m"
+	colorCyan   = "\x1b[36m"
+
+	// messageWidth is a minimal width of message column in aligned text.
+	messageWidth = 40
+)
+
+func levelLabel(level string) (label, color string) {
+	switch level {
+	case "debug":
+		return "DEBUG", colorGray
+	case "warn":
+		return "WARN", colorYellow
+	case "error":
+		return "ERROR", colorRed
+	default:
+		return "INFO", colorBlue
+	}
+}
+
+func (l *ConsoleLogger) minLevel() Level {
+	if l.DebugEnabled {
+		return DebugLevel
+	}
+
+	return InfoLevel
+}
+
+func (l *ConsoleLogger) log(ctx context.Context, level, msg string, keysAndValues []interface{}) {
+	l.once.Do(l.init)
+
+	fp := acquireFields(ctx, keysAndValues)
+	sortFields(*fp)
+
+	bp := acquireBuf()
+	b := *bp
+
+	if l.Logfmt {
+		b = l.appendLogfmt(b, level, msg, *fp)
+	} else {
+		b = l.appendText(b, level, msg, *fp, l.colored && (l.Colors == ColorAlways || LogWriter(ctx) == nil))
+	}
+
+	if err := writeEntry(ctx, &l.mu, l.Output, b); err != nil && l.OnError != nil {
+		l.OnError(err)
+	}
+
+	releaseFields(fp)
+
+	*bp = b
+	releaseBuf(bp)
+}
+
+func (l *ConsoleLogger) appendLogfmt(b []byte, level, msg string, fields []field) []byte {
+	b = append(b, "time="...)
+	b = timeNow(l.TimeNow).AppendFormat(b, l.timeFormat)
+	b = append(b, " level="...)
+	b = append(b, level...)
+	b = append(b, " msg="...)
+	b = appendLogfmtString(b, msg)
+
+	for _, f := range fields {
+		b = append(b, ' ')
+		b = appendLogfmtKey(b, f.key)
+		b = append(b, '=')
+		b = appendLogfmtValue(b, f.value)
+	}
+
+	return append(b, '\n')
+}
+
+func (l *ConsoleLogger) appendText(b []byte, level, msg string, fields []field, colored bool) []byte {
+	if colored {
+		b = append(b, colorGray...)
+	}
+
+	b = timeNow(l.TimeNow).AppendFormat(b, l.timeFormat)
+
+	if colored {
+		b = append(b, colorReset...)
+	}
+
+	b = append(b, ' ')
+
+	lvl, color := levelLabel(level)
+
+	if colored {
+		b = append(b, color...)
+	}
+
+	b = append(b, lvl...)
+
+	if colored {
+		b = append(b, colorReset...)
+	}
+
+	for i := len(lvl); i < 6; i++ {
+		b = append(b, ' ')
+	}
+
+	start := len(b)
+	b = appendTextMessage(b, msg)
+
+	if len(fields) > 0 {
+		for i := len(b) - start; i < messageWidth; i++ {
+			b = append(b, ' ')
+		}
+	}
+
+	for _, f := range fields {
+		b = append(b, ' ')
+
+		if colored {
+			b = append(b, colorCyan...)
+		}
+
+		b = appendLogfmtKey(b, f.key)
+		b = append(b, '=')
+
+		if colored {
+			b = append(b, colorReset...)
+		}
+
+		b = appendLogfmtValue(b, f.value)
+	}
+
+	return append(b, '\n')
+}
+
+// sortFields sorts fields by key, insertion sort is used to avoid allocations on small slices.
+func sortFields(fields []field) {
+	for i := 1; i < len(fields); i++ {
+		for j := i; j > 0 && fields[j].key < fields[j-1].key; j-- {
+			fields[j], fields[j-1] = fields[j-1], fields[j]
+		}
+	}
+}
+
+func appendLogfmtValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendLogfmtString(b, v)
+	case time.Duration:
+		return append(b, v.String()...)
+	case time.Time:
+		return v.AppendFormat(b, time.RFC3339Nano)
+	case error:
+		if !isNilPointer(v) {
+			return appendLogfmtString(b, v.Error())
+		}
+	case fmt.Stringer:
+		if !isNilPointer(v) {
+			return appendLogfmtString(b, v.String())
+		}
+	}
+
+	start := len(b)
+	b = appendJSONValue(b, v)
+
+	// JSON strings are already quoted, other values need quoting if they contain spaces or quotes.
+	if b[start] != '"' && bytes.ContainsAny(b[start:], " \"=") {
+		s := string(b[start:])
+		b = appendJSONString(b[:start], s)
+	}
+
+	return b
+}
+
+// appendTextMessage appends message to b, escaping control characters to keep entry on a single line.
+func appendTextMessage(b []byte, msg string) []byte {
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+
+		switch {
+		case c == '\n':
+			b = append(b, `\n`...)
+		case c == '\r':
+			b = append(b, `\r`...)
+		case c == '\t':
+			b = append(b, `\t`...)
+		case c < ' ' || c == 0x7f:
+			b = append(b, `\x`...)
+			b = append(b, hex[c>>4], hex[c&0xF])
+		default:
+			b = append(b, c)
+		}
+	}
+
+	return b
+}
+
+// appendLogfmtKey appends key to b, replacing spaces, '=', '"' and control characters with '_'.
+func appendLogfmtKey(b []byte, key string) []byte {
+	if key == "" {
+		return append(b, '_')
+	}
+
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+			b = append(b, '_')
+		} else {
+			b = append(b, c)
+		}
+	}
+
+	return b
+}
+
+// appendLogfmtString appends string to b, quoting it if necessary.
+func appendLogfmtString(b []byte, s string) []byte {
+	if s == "" {
+		return append(b, `""`...)
+	}
+
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c <= ' ' || c == '=' || c == '"' || c == '\\' || c >= 0x7f {
+			return appendJSONString(b, s)
+		}
+	}
+
+	return append(b, s...)
+}
@@ -0,0 +1,146 @@
+package ctxd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleLogger(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.ConsoleLogger{
+		Output:     &buf,
+		TimeFormat: "-",
+	}
+
+	ctx := ctxd.AddFields(context.Background(), "foo", 1, "bar", "abc")
+
+	l.Debug(ctx, "debug", "baz", 3)
+	l.Info(ctx, "info", "baz", 3, "bar", "def ghi")
+	l.Important(ctx, "important", "baz", 3)
+	l.Warn(ctx, "warn", "baz", 3)
+	l.Error(ctx, "error", "baz", 3, "err", errors.New("failed"))
+	l.Debug(ctxd.WithDebug(ctx), "debug with debug", "baz", 3)
+	l.Info(context.Background(), "no fields")
+
+	assert.Equal(t, `- INFO  info                                     bar="def ghi" baz=3 foo=1
+- INFO  important                                bar=abc baz=3 foo=1
+- WARN  warn                                     bar=abc baz=3 foo=1
+- ERROR error                                    bar=abc baz=3 err=failed foo=1
+- DEBUG debug with debug                         bar=abc baz=3 foo=1
+- INFO  no fields
+`, buf.String())
+}
+
+func TestConsoleLogger_colors(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.ConsoleLogger{
+		Output:       &buf,
+		TimeFormat:   "-",
+		Colors:       ctxd.ColorAlways,
+		DebugEnabled: true,
+	}
+
+	l.Debug(context.Background(), "debug", "a", 1)
+	l.Warn(context.Background(), "warn", "a", 1)
+
+	assert.Equal(t, "\x1b[90m-\x1b[0m \x1b[90mDEBUG\x1b[0m debug                                    \x1b[36ma=\x1b[0m1\n"+
+		"\x1b[90m-\x1b[0m \x1b[33mWARN\x1b[0m  warn                                     \x1b[36ma=\x1b[0m1\n", buf.String())
+
+	buf.Reset()
+
+	l = ctxd.ConsoleLogger{Output: &buf, TimeFormat: "-"}
+	l.Info(context.Background(), "auto colors disabled for non-terminal")
+	assert.Equal(t, "- INFO  auto colors disabled for non-terminal\n", buf.String())
+}
+
+func TestConsoleLogger_Logfmt(t *testing.T) {
+	buf := bytes.Buffer{}
+	ctxBuf := bytes.Buffer{}
+	l := ctxd.ConsoleLogger{
+		Output:     &buf,
+		Logfmt:     true,
+		TimeFormat: "-",
+	}
+
+	ctx := ctxd.AddFields(context.Background(), "foo", 1)
+
+	l.Info(ctx, "hello world",
+		"str", "a=b",
+		"empty", "",
+		"dur", 1500*time.Millisecond,
+		"time", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		"slice", []string{"a b", "c"},
+		"map", map[string]int{"a": 1},
+		"deferred", ctxd.DeferredString(func() interface{} { return "x y" }),
+	)
+
+	l.Warn(ctxd.WithLogWriter(ctx, &ctxBuf), "to context writer")
+
+	assert.Equal(t, `time=- level=info msg="hello world" deferred="x y" dur=1.5s empty="" foo=1 map="{\"a\":1}" `+
+		`slice="[\"a b\",\"c\"]" str="a=b" time=2020-01-02T03:04:05Z`+"\n", buf.String())
+	assert.Equal(t, "time=- level=warn msg=\"to context writer\" foo=1\n", ctxBuf.String())
+}
+
+func BenchmarkConsoleLogger_Info(b *testing.B) {
+	l := ctxd.ConsoleLogger{Output: io.Discard}
+	ctx := ctxd.AddFields(context.Background(), "foo", 1, "bar", "abc")
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		l.Info(ctx, "hello", "baz", 3, "quux", true)
+	}
+}
+
+func TestConsoleLogger_TimeNow(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.ConsoleLogger{
+		Output:  &buf,
+		Logfmt:  true,
+		TimeNow: fakeClock(),
+	}
+
+	l.Info(context.Background(), "first")
+	l.Warn(context.Background(), "second", "k", 1)
+
+	assert.Equal(t, `time=2020-01-02T03:04:06Z level=info msg=first
+time=2020-01-02T03:04:07Z level=warn msg=second k=1
+`, buf.String())
+}
+
+func TestConsoleLogger_nilPointers(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.ConsoleLogger{Output: &buf, Logfmt: true, TimeFormat: "-"}
+
+	l.Info(context.Background(), "m",
+		"err", (*fieldErr)(nil),
+		"stringer", (*fieldStringer)(nil),
+		"marshaler", (*fieldMarshaler)(nil),
+		"ok", &fieldErr{msg: "failed"},
+	)
+
+	assert.Equal(t, "time=- level=info msg=m err=null marshaler=null ok=failed stringer=null\n", buf.String())
+}
+
+func TestConsoleLogger_escaping(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.ConsoleLogger{Output: &buf, TimeFormat: "-", Colors: ctxd.ColorNever}
+
+	l.Info(context.Background(), "a\nERROR forged\x1b", "bad key=x", "v", "k\n\"", 1)
+
+	assert.Equal(t, `- INFO  a\nERROR forged\x1b                      bad_key_x=v k__=1`+"\n", buf.String())
+
+	buf.Reset()
+
+	l = ctxd.ConsoleLogger{Output: &buf, TimeFormat: "-", Logfmt: true}
+	l.Info(context.Background(), "a\nb", "bad key=x", "v")
+
+	assert.Equal(t, `time=- level=info msg="a\nb" bad_key_x=v`+"\n", buf.String())
+}
@@ -0,0 +1,2 @@
+// Package ctxdhttp provides net/http instrumentation for contextualized logging.
+package ctxdhttp
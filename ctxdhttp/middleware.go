@@ -0,0 +1,210 @@
+package ctxdhttp
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bool64/ctxd"
+)
+
+// AccessLogMessage is a default message of access log entry.
+const AccessLogMessage = "http request"
+
+// ErrHijackNotSupported is returned by Hijack of wrapped ResponseWriter that does not implement http.Hijacker.
+const ErrHijackNotSupported = ctxd.SentinelError("hijack not supported")
+
+// Config defines Middleware behavior.
+type Config struct {
+	// Logger receives access log entries, access log is disabled if nil.
+	Logger ctxd.Logger
+
+	// FieldNames defines names of request and response fields, empty names are replaced with defaults.
+	FieldNames ctxd.FieldNames
+
+	// TrustedProxies are networks of reverse proxies that are allowed to set X-Forwarded-For header.
+	// X-Forwarded-For is ignored if empty.
+	TrustedProxies []*net.IPNet
+
+	// AccessLogMessage is a message of access log entry, default AccessLogMessage.
+	AccessLogMessage string
+
+	// AccessLogLevel returns level of access log entry for response status, default InfoLevel.
+	AccessLogLevel func(status int) ctxd.Level
+
+	// SkipAccessLog disables access log entry for a request if returns true, e.g. for health checks.
+	SkipAccessLog func(r *http.Request) bool
+
+	// TimeNow returns current time to measure request duration, time.Now is used if nil.
+	TimeNow func() time.Time
+}
+
+// Middleware instruments http.Handler with contextualized request fields and access log.
+//
+// Client IP, method, URL and user agent of request are added to request context with ctxd.AddFields,
+// so that they are available to loggers and errors of handler.
+// Access log entry additionally contains response status, number of written bytes and request duration.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	names := cfg.FieldNames.WithDefaults()
+
+	if cfg.AccessLogMessage == "" {
+		cfg.AccessLogMessage = AccessLogMessage
+	}
+
+	if cfg.TimeNow == nil {
+		cfg.TimeNow = time.Now
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := cfg.TimeNow()
+
+			ctx := ctxd.AddFields(r.Context(),
+				names.ClientIP, ClientIP(r, cfg.TrustedProxies),
+				names.HTTPMethod, r.Method,
+				names.URL, r.URL.String(),
+				names.UserAgentOriginal, r.UserAgent(),
+			)
+			r = r.WithContext(ctx)
+
+			if cfg.Logger == nil || (cfg.SkipAccessLog != nil && cfg.SkipAccessLog(r)) {
+				next.ServeHTTP(rw, r)
+
+				return
+			}
+
+			w := &responseWriter{ResponseWriter: rw}
+
+			next.ServeHTTP(w, r)
+
+			status := w.Status()
+			level := ctxd.InfoLevel
+
+			if cfg.AccessLogLevel != nil {
+				level = cfg.AccessLogLevel(status)
+			}
+
+			ctxd.Log(ctx, cfg.Logger, level, cfg.AccessLogMessage,
+				names.HTTPResponseStatus, status,
+				names.HTTPResponseBytes, w.bytes,
+				names.EventDuration, cfg.TimeNow().Sub(start),
+			)
+		})
+	}
+}
+
+// ClientIP returns IP address of request client.
+//
+// If request comes from a trusted proxy, X-Forwarded-For header is inspected from right to left
+// and the first address that is not a trusted proxy is returned.
+func ClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	if len(trustedProxies) == 0 || !trusted(net.ParseIP(ip), trustedProxies) {
+		return ip
+	}
+
+	xff := r.Header.Values("X-Forwarded-For")
+
+	for i := len(xff) - 1; i >= 0; i-- {
+		hops := strings.Split(xff[i], ",")
+
+		for j := len(hops) - 1; j >= 0; j-- {
+			hop := strings.TrimSpace(hops[j])
+
+			parsed := net.ParseIP(hop)
+			if parsed == nil {
+				// Malformed header value can not be trusted further.
+				return ip
+			}
+
+			ip = hop
+
+			if !trusted(parsed, trustedProxies) {
+				return ip
+			}
+		}
+	}
+
+	return ip
+}
+
+func trusted(ip net.IP, trustedProxies []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// responseWriter captures status and number of written bytes.
+type responseWriter struct {
+	http.ResponseWriter
+
+	status int
+	bytes  int
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
+
+// Status returns response status, http.StatusOK is assumed if it was not written.
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+// Flush implements http.Flusher.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, ErrHijackNotSupported
+}
+
+// Unwrap returns original ResponseWriter for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
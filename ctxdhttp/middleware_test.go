@@ -0,0 +1,132 @@
+package ctxdhttp_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/ctxd/ctxdhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	var (
+		m       ctxd.LoggerMock
+		handled bool
+	)
+
+	ts := time.Now()
+
+	h := ctxdhttp.Middleware(ctxdhttp.Config{
+		Logger: &m,
+		TimeNow: func() time.Time {
+			ts = ts.Add(time.Second)
+
+			return ts
+		},
+		AccessLogLevel: func(status int) ctxd.Level {
+			if status >= http.StatusInternalServerError {
+				return ctxd.ErrorLevel
+			}
+
+			return ctxd.InfoLevel
+		},
+	})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		handled = true
+
+		assert.Equal(t, []interface{}{
+			"client.ip", "192.0.2.1",
+			"http.request.method", http.MethodPost,
+			"url.original", "/foo?bar=baz",
+			"user_agent.original", "test-agent",
+		}, ctxd.Fields(r.Context()))
+
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		_, err := rw.Write([]byte("hello"))
+		assert.NoError(t, err)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/foo?bar=baz", nil)
+	req.Header.Set("User-Agent", "test-agent")
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.True(t, handled)
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+
+	entries := m.LoggedEntries
+	require.Len(t, entries, 1)
+
+	e := entries[0]
+	assert.Equal(t, "error", e.Level)
+	assert.Equal(t, ctxdhttp.AccessLogMessage, e.Message)
+	assert.Equal(t, "192.0.2.1", e.Data["client.ip"])
+	assert.Equal(t, http.StatusServiceUnavailable, e.Data["http.response.status_code"])
+	assert.Equal(t, 5, e.Data["http.response.bytes"])
+	assert.Equal(t, time.Second, e.Data["event.duration"])
+}
+
+func TestMiddleware_skip(t *testing.T) {
+	m := ctxd.LoggerMock{}
+
+	h := ctxdhttp.Middleware(ctxdhttp.Config{
+		Logger:           &m,
+		AccessLogMessage: "access",
+		SkipAccessLog: func(r *http.Request) bool {
+			return r.URL.Path == "/health"
+		},
+	})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	entries := m.LoggedEntries
+	require.Len(t, entries, 1)
+	assert.Equal(t, "access", entries[0].Message)
+	assert.Equal(t, "/", entries[0].Data["url.original"])
+	assert.Equal(t, http.StatusOK, entries[0].Data["http.response.status_code"])
+	assert.Equal(t, 0, entries[0].Data["http.response.bytes"])
+}
+
+func TestClientIP(t *testing.T) {
+	_, private, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	trusted := []*net.IPNet{private}
+
+	for _, tc := range []struct {
+		name    string
+		remote  string
+		xff     []string
+		trusted []*net.IPNet
+		ip      string
+	}{
+		{name: "no proxies", remote: "10.0.0.1:1234", xff: []string{"192.0.2.1"}, ip: "10.0.0.1"},
+		{name: "untrusted remote", remote: "192.0.2.2:1234", xff: []string{"192.0.2.1"}, trusted: trusted, ip: "192.0.2.2"},
+		{name: "trusted remote", remote: "10.0.0.1:1234", xff: []string{"192.0.2.1"}, trusted: trusted, ip: "192.0.2.1"},
+		{name: "spoofed", remote: "10.0.0.1:1234", xff: []string{"198.51.100.1, 192.0.2.1, 10.0.0.2"}, trusted: trusted, ip: "192.0.2.1"},
+		{name: "multiple headers", remote: "10.0.0.1:1234", xff: []string{"192.0.2.1", "10.0.0.2"}, trusted: trusted, ip: "192.0.2.1"},
+		{name: "all trusted", remote: "10.0.0.1:1234", xff: []string{"10.0.0.3, 10.0.0.2"}, trusted: trusted, ip: "10.0.0.3"},
+		{name: "malformed", remote: "10.0.0.1:1234", xff: []string{"192.0.2.1, bad"}, trusted: trusted, ip: "10.0.0.1"},
+		{name: "no header", remote: "10.0.0.1:1234", trusted: trusted, ip: "10.0.0.1"},
+		{name: "no port", remote: "10.0.0.1", ip: "10.0.0.1"},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tc.remote
+
+			for _, v := range tc.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+
+			assert.Equal(t, tc.ip, ctxdhttp.ClientIP(r, tc.trusted))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package ctxdhttp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bool64/ctxd"
+)
+
+// RequestIDHeader is a default name of request ID header.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength limits length of incoming request ID.
+const maxRequestIDLength = 128
+
+// RequestIDConfig defines RequestIDMiddleware behavior.
+type RequestIDConfig struct {
+	// Header is a name of request and response header with request ID, default RequestIDHeader.
+	Header string
+
+	// FieldName is a name of context field with request ID, default "transaction.id" (FieldNames.TransactionID).
+	FieldName string
+
+	// Generate returns new request ID, default is a random 128-bit hex string.
+	Generate func() string
+
+	// IgnoreIncoming disables reading request ID from request header, new ID is always generated.
+	IgnoreIncoming bool
+}
+
+type requestIDCtxKey struct{}
+
+// WithRequestID returns context with request ID.
+//
+// Request ID is also set as a context field with name of FieldNames.TransactionID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return withRequestID(ctx, ctxd.DefaultFieldNames().TransactionID, id)
+}
+
+func withRequestID(ctx context.Context, fieldName, id string) context.Context {
+	return context.WithValue(ctxd.SetFields(ctx, fieldName, id), requestIDCtxKey{}, id)
+}
+
+// RequestID returns request ID from context or empty string.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey{}).(string)
+
+	return id
+}
+
+// NewRequestID returns a random 128-bit hex string.
+func NewRequestID() string {
+	var b [16]byte
+
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+
+	return hex.EncodeToString(b[:])
+}
+
+// RequestIDMiddleware instruments http.Handler with request ID.
+//
+// Request ID is read from request header or generated if missing or invalid,
+// then it is added to request context (see RequestID) and echoed in response header.
+// Incoming request ID is only accepted if it consists of up to 128 printable ASCII characters.
+func RequestIDMiddleware(cfg RequestIDConfig) func(http.Handler) http.Handler {
+	if cfg.Header == "" {
+		cfg.Header = RequestIDHeader
+	}
+
+	if cfg.FieldName == "" {
+		cfg.FieldName = ctxd.DefaultFieldNames().TransactionID
+	}
+
+	if cfg.Generate == nil {
+		cfg.Generate = NewRequestID
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var id string
+
+			if !cfg.IgnoreIncoming {
+				id = r.Header.Get(cfg.Header)
+			}
+
+			if !validRequestID(id) {
+				id = cfg.Generate()
+			}
+
+			rw.Header().Set(cfg.Header, id)
+
+			next.ServeHTTP(rw, r.WithContext(withRequestID(r.Context(), cfg.FieldName, id)))
+		})
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if c := id[i]; c < '!' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RequestIDTransport is a http.RoundTripper that forwards request ID from context to outgoing requests.
+type RequestIDTransport struct {
+	// Transport performs requests, http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+
+	// Header is a name of request ID header, default RequestIDHeader.
+	Header string
+}
+
+var _ http.RoundTripper = RequestIDTransport{}
+
+// RoundTrip adds request ID header if it is available in context and is not set in request.
+func (t RequestIDTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	tr := t.Transport
+	if tr == nil {
+		tr = http.DefaultTransport
+	}
+
+	header := t.Header
+	if header == "" {
+		header = RequestIDHeader
+	}
+
+	if id := RequestID(r.Context()); id != "" && r.Header.Get(header) == "" {
+		// Request is cloned as RoundTripper should not modify original request.
+		r = r.Clone(r.Context())
+		r.Header.Set(header, id)
+	}
+
+	return tr.RoundTrip(r)
+}
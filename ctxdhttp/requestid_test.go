@@ -0,0 +1,110 @@
+package ctxdhttp_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/ctxd/ctxdhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var ctx context.Context
+
+	h := ctxdhttp.RequestIDMiddleware(ctxdhttp.RequestIDConfig{})(
+		http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			ctx = r.Context()
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-Id", "abc-123")
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, "abc-123", ctxdhttp.RequestID(ctx))
+	assert.Equal(t, []interface{}{"transaction.id", "abc-123"}, ctxd.Fields(ctx))
+	assert.Equal(t, "abc-123", rw.Header().Get("X-Request-Id"))
+
+	for _, id := range []string{"", "with space", strings.Repeat("a", 129)} {
+		req = httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-Id", id)
+
+		rw = httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		generated := ctxdhttp.RequestID(ctx)
+		assert.Len(t, generated, 32)
+		assert.Equal(t, generated, rw.Header().Get("X-Request-Id"))
+	}
+}
+
+func TestRequestIDMiddleware_config(t *testing.T) {
+	var ctx context.Context
+
+	h := ctxdhttp.RequestIDMiddleware(ctxdhttp.RequestIDConfig{
+		Header:         "X-Trace",
+		FieldName:      "request.id",
+		IgnoreIncoming: true,
+		Generate: func() string {
+			return "generated"
+		},
+	})(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Trace", "incoming")
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, req)
+
+	assert.Equal(t, "generated", ctxdhttp.RequestID(ctx))
+	assert.Equal(t, []interface{}{"request.id", "generated"}, ctxd.Fields(ctx))
+	assert.Equal(t, "generated", rw.Header().Get("X-Trace"))
+}
+
+func TestRequestIDTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte(r.Header.Get("X-Request-Id")))
+	}))
+	defer srv.Close()
+
+	c := http.Client{Transport: ctxdhttp.RequestIDTransport{}}
+
+	do := func(ctx context.Context, id string) string {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+
+		if id != "" {
+			req.Header.Set("X-Request-Id", id)
+		}
+
+		resp, err := c.Do(req)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, resp.Body.Close())
+		}()
+
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, id, req.Header.Get("X-Request-Id"), "original request is not modified")
+
+		return string(b)
+	}
+
+	ctx := ctxdhttp.WithRequestID(context.Background(), "abc")
+
+	assert.Equal(t, "abc", do(ctx, ""))
+	assert.Equal(t, "explicit", do(ctx, "explicit"))
+	assert.Equal(t, "", do(context.Background(), ""))
+}
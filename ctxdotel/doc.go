@@ -0,0 +1,4 @@
+// Package ctxdotel correlates contextualized logs and errors with OpenTelemetry traces.
+//
+// Package is a separate module to keep ctxd free of OpenTelemetry dependencies.
+package ctxdotel
@@ -0,0 +1,182 @@
+package ctxdotel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/bool64/ctxd"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config defines behavior of instrumented logger.
+type Config struct {
+	// FieldNames defines names of trace and span ID fields, empty names are replaced with defaults.
+	FieldNames ctxd.FieldNames
+
+	// RecordErrors enables recording of Error messages as events of active span,
+	// message is used as event name and fields as event attributes.
+	RecordErrors bool
+}
+
+// TraceFields returns trace and span ID fields of active span in context with default names.
+//
+// Nil is returned if context has no valid span context.
+func TraceFields(ctx context.Context) []interface{} {
+	return traceFields(ctx, ctxd.DefaultFieldNames())
+}
+
+func traceFields(ctx context.Context, names ctxd.FieldNames) []interface{} {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return []interface{}{
+		names.TraceID, sc.TraceID().String(),
+		names.SpanID, sc.SpanID().String(),
+	}
+}
+
+// ContextWithTrace returns context with trace and span ID fields of active span.
+//
+// Fields with the same names that already exist in context are replaced.
+func ContextWithTrace(ctx context.Context) context.Context {
+	if kv := TraceFields(ctx); kv != nil {
+		return ctxd.SetFields(ctx, kv...)
+	}
+
+	return ctx
+}
+
+// NewError creates error with ctxd.NewError and trace fields of active span.
+func NewError(ctx context.Context, message string, keysAndValues ...interface{}) error {
+	return ctxd.NewError(ContextWithTrace(ctx), message, keysAndValues...)
+}
+
+// WrapError wraps error with ctxd.WrapError and trace fields of active span.
+func WrapError(ctx context.Context, err error, message string, keysAndValues ...interface{}) error {
+	return ctxd.WrapError(ContextWithTrace(ctx), err, message, keysAndValues...)
+}
+
+// NewLogger instruments contextualized logger with trace and span ID fields of active span.
+//
+// Fields of structured errors logged with ctxd.LogError take precedence,
+// so that error keeps trace of its creation.
+func NewLogger(logger ctxd.Logger, cfg Config) ctxd.Logger {
+	return &tracingLogger{
+		logger: logger,
+		names:  cfg.FieldNames.WithDefaults(),
+		record: cfg.RecordErrors,
+	}
+}
+
+type tracingLogger struct {
+	logger ctxd.Logger
+	names  ctxd.FieldNames
+	record bool
+}
+
+func (l *tracingLogger) ctx(ctx context.Context) context.Context {
+	if kv := traceFields(ctx, l.names); kv != nil {
+		return ctxd.SetFields(ctx, kv...)
+	}
+
+	return ctx
+}
+
+func (l *tracingLogger) Enabled(ctx context.Context, level ctxd.Level) bool {
+	return ctxd.Enabled(ctx, l.logger, level)
+}
+
+func (l *tracingLogger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logger.Debug(l.ctx(ctx), msg, keysAndValues...)
+}
+
+func (l *tracingLogger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logger.Info(l.ctx(ctx), msg, keysAndValues...)
+}
+
+func (l *tracingLogger) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logger.Important(l.ctx(ctx), msg, keysAndValues...)
+}
+
+func (l *tracingLogger) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.logger.Warn(l.ctx(ctx), msg, keysAndValues...)
+}
+
+func (l *tracingLogger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if l.record {
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			span.AddEvent(msg, trace.WithAttributes(attributes(ctx, keysAndValues)...))
+		}
+	}
+
+	l.logger.Error(l.ctx(ctx), msg, keysAndValues...)
+}
+
+// attributes converts fields of context and keys and values to span attributes sorted by key.
+func attributes(ctx context.Context, keysAndValues []interface{}) []attribute.KeyValue {
+	fields := ctxd.Tuples(append(ctxd.Fields(ctx), keysAndValues...)).Fields()
+	if len(fields) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, attributeValue(k, fields[k]))
+	}
+
+	return attrs
+}
+
+func attributeValue(k string, v interface{}) attribute.KeyValue {
+	switch v := v.(type) {
+	case string:
+		return attribute.String(k, v)
+	case bool:
+		return attribute.Bool(k, v)
+	case int:
+		return attribute.Int(k, v)
+	case int64:
+		return attribute.Int64(k, v)
+	case int32:
+		return attribute.Int64(k, int64(v))
+	case float64:
+		return attribute.Float64(k, v)
+	case float32:
+		return attribute.Float64(k, float64(v))
+	case []string:
+		return attribute.StringSlice(k, v)
+	case error:
+		if isNilPointer(v) {
+			return attribute.String(k, "<nil>")
+		}
+
+		return attribute.String(k, v.Error())
+	case fmt.Stringer:
+		if isNilPointer(v) {
+			return attribute.String(k, "<nil>")
+		}
+
+		return attribute.String(k, v.String())
+	default:
+		return attribute.String(k, fmt.Sprintf("%+v", v))
+	}
+}
+
+// isNilPointer checks if v is a typed nil pointer, calling methods of such value may panic.
+func isNilPointer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
@@ -0,0 +1,140 @@
+package ctxdotel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/ctxd/ctxdotel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracer(t *testing.T) (*tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	t.Helper()
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+
+	t.Cleanup(func() {
+		require.NoError(t, tp.Shutdown(context.Background()))
+	})
+
+	return exp, tp
+}
+
+func TestNewLogger(t *testing.T) {
+	exp, tp := newTracer(t)
+	m := ctxd.LoggerMock{}
+	l := ctxdotel.NewLogger(&m, ctxdotel.Config{RecordErrors: true})
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	ctx = ctxd.AddFields(ctx, "foo", "bar")
+
+	traceID := span.SpanContext().TraceID().String()
+	spanID := span.SpanContext().SpanID().String()
+
+	l.Info(ctx, "info", "n", 1)
+	l.Error(ctx, "failed", "n", 2, "err", errors.New("oops"))
+	l.Info(context.Background(), "no span")
+
+	span.End()
+
+	require.Len(t, m.LoggedEntries, 3)
+	assert.Equal(t, map[string]interface{}{
+		"foo": "bar", "n": 1, "trace.id": traceID, "span.id": spanID,
+	}, m.LoggedEntries[0].Data)
+	assert.Equal(t, traceID, m.LoggedEntries[1].Data["trace.id"])
+	assert.Nil(t, m.LoggedEntries[2].Data)
+
+	spans := exp.GetSpans()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Events, 1)
+
+	e := spans[0].Events[0]
+	assert.Equal(t, "failed", e.Name)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("err", "oops"),
+		attribute.String("foo", "bar"),
+		attribute.Int("n", 2),
+	}, e.Attributes)
+}
+
+func TestNewLogger_fieldNames(t *testing.T) {
+	_, tp := newTracer(t)
+	m := ctxd.LoggerMock{}
+	l := ctxdotel.NewLogger(&m, ctxdotel.Config{FieldNames: ctxd.FieldNames{TraceID: "trace_id"}})
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	assert.True(t, ctxd.Enabled(ctx, l, ctxd.DebugLevel))
+
+	l.Warn(ctx, "warn")
+
+	require.Len(t, m.LoggedEntries, 1)
+	assert.Equal(t, span.SpanContext().TraceID().String(), m.LoggedEntries[0].Data["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), m.LoggedEntries[0].Data["span.id"])
+}
+
+func TestNewError(t *testing.T) {
+	_, tp := newTracer(t)
+	m := ctxd.LoggerMock{}
+	l := ctxdotel.NewLogger(&m, ctxdotel.Config{})
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	err := ctxdotel.NewError(ctx, "failed", "k", "v")
+	wrapped := ctxdotel.WrapError(ctx, errors.New("oops"), "wrapped")
+
+	span.End()
+
+	var se ctxd.StructuredError
+
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, span.SpanContext().TraceID().String(), se.Fields()["trace.id"])
+	assert.Equal(t, "v", se.Fields()["k"])
+
+	require.True(t, errors.As(wrapped, &se))
+	assert.Equal(t, span.SpanContext().SpanID().String(), se.Fields()["span.id"])
+
+	// Error logged in another span keeps trace of its creation.
+	ctx2, span2 := tp.Tracer("test").Start(context.Background(), "other")
+	defer span2.End()
+
+	ctxd.LogError(ctx2, err, l.Error)
+
+	require.Len(t, m.LoggedEntries, 1)
+	assert.Equal(t, span.SpanContext().SpanID().String(), m.LoggedEntries[0].Data["span.id"])
+
+	assert.Nil(t, ctxdotel.TraceFields(context.Background()))
+	assert.Equal(t, []interface{}{
+		"trace.id", span2.SpanContext().TraceID().String(),
+		"span.id", span2.SpanContext().SpanID().String(),
+	}, ctxd.Fields(ctxdotel.ContextWithTrace(ctx2)))
+}
+
+type fieldErr struct {
+	msg string
+}
+
+func (e *fieldErr) Error() string {
+	return e.msg
+}
+
+func TestNewLogger_nilPointer(t *testing.T) {
+	exp, tp := newTracer(t)
+	l := ctxdotel.NewLogger(ctxd.NoOpLogger{}, ctxdotel.Config{RecordErrors: true})
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	l.Error(ctx, "failed", "err", (*fieldErr)(nil))
+	span.End()
+
+	spans := exp.GetSpans()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Events, 1)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("err", "<nil>")}, spans[0].Events[0].Attributes)
+}
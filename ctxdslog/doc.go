@@ -0,0 +1,4 @@
+// Package ctxdslog bridges ctxd.Logger and log/slog in both directions.
+//
+// Package requires Go 1.21 or later.
+package ctxdslog
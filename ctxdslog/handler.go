@@ -0,0 +1,165 @@
+//go:build go1.21
+// +build go1.21
+
+package ctxdslog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/bool64/ctxd"
+)
+
+// LevelImportant is a custom slog level that represents ctxd.Logger Important messages.
+//
+// Important messages bypass level filtering.
+const LevelImportant = slog.LevelInfo + 2
+
+// HandlerOptions configures Handler.
+type HandlerOptions struct {
+	// Level is a minimal level of records to handle, slog.LevelInfo is used if nil.
+	// Records with LevelImportant are always handled.
+	Level slog.Leveler
+}
+
+// Handler is a slog.Handler that writes records to ctxd.Logger.
+//
+// Record levels are mapped to ctxd.Logger methods:
+// below slog.LevelInfo to Debug, below slog.LevelWarn to Info (LevelImportant to Important),
+// below slog.LevelError to Warn and the rest to Error.
+//
+// Attributes are passed as keys and values, group names are added to keys as dot-separated prefixes.
+type Handler struct {
+	logger        ctxd.Logger
+	level         slog.Leveler
+	prefix        string
+	keysAndValues []interface{}
+}
+
+var _ slog.Handler = &Handler{}
+
+// NewHandler creates slog.Handler backed by ctxd.Logger.
+func NewHandler(logger ctxd.Logger, options *HandlerOptions) *Handler {
+	h := &Handler{
+		logger: logger,
+		level:  slog.LevelInfo,
+	}
+
+	if options != nil && options.Level != nil {
+		h.level = options.Level
+	}
+
+	return h
+}
+
+// Enabled reports whether handler handles records at the given level.
+//
+// Level from context (see ctxd.WithLevel) takes precedence over handler level.
+// Records are also disabled if underlying logger reports disabled level (see ctxd.LevelEnabler).
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level == LevelImportant {
+		return true
+	}
+
+	if l, ok := ctxd.LevelFrom(ctx); ok {
+		if ctxdLevel(level) < l {
+			return false
+		}
+	} else if level < h.level.Level() {
+		return false
+	}
+
+	return ctxd.Enabled(ctx, h.logger, ctxdLevel(level))
+}
+
+// ctxdLevel maps slog level to ctxd level.
+func ctxdLevel(level slog.Level) ctxd.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return ctxd.DebugLevel
+	case level < slog.LevelWarn:
+		return ctxd.InfoLevel
+	case level < slog.LevelError:
+		return ctxd.WarnLevel
+	default:
+		return ctxd.ErrorLevel
+	}
+}
+
+// Handle sends record to ctxd.Logger.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	kv := make([]interface{}, 0, len(h.keysAndValues)+2*r.NumAttrs())
+	kv = append(kv, h.keysAndValues...)
+
+	r.Attrs(func(a slog.Attr) bool {
+		kv = appendAttr(kv, h.prefix, a)
+
+		return true
+	})
+
+	switch {
+	case r.Level == LevelImportant:
+		h.logger.Important(ctx, r.Message, kv...)
+	case r.Level < slog.LevelInfo:
+		h.logger.Debug(ctx, r.Message, kv...)
+	case r.Level < slog.LevelWarn:
+		h.logger.Info(ctx, r.Message, kv...)
+	case r.Level < slog.LevelError:
+		h.logger.Warn(ctx, r.Message, kv...)
+	default:
+		h.logger.Error(ctx, r.Message, kv...)
+	}
+
+	return nil
+}
+
+// WithAttrs returns a new handler with attributes added to every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.keysAndValues = slices.Clip(h.keysAndValues)
+
+	for _, a := range attrs {
+		h2.keysAndValues = appendAttr(h2.keysAndValues, h.prefix, a)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new handler that prefixes keys of subsequent attributes with group name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+
+	return &h2
+}
+
+func appendAttr(kv []interface{}, prefix string, a slog.Attr) []interface{} {
+	a.Value = a.Value.Resolve()
+
+	if a.Equal(slog.Attr{}) {
+		return kv
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		return append(kv, prefix+a.Key, a.Value.Any())
+	}
+
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+
+	for _, ga := range a.Value.Group() {
+		kv = appendAttr(kv, prefix, ga)
+	}
+
+	return kv
+}
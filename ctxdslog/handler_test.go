@@ -0,0 +1,72 @@
+//go:build go1.21
+// +build go1.21
+
+package ctxdslog_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/ctxd/ctxdslog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := slog.New(ctxdslog.NewHandler(&lm, &ctxdslog.HandlerOptions{Level: slog.LevelDebug}))
+
+	ctx := ctxd.AddFields(context.Background(), "foo", 1)
+
+	l.DebugContext(ctx, "debug", "a", 1)
+	l.InfoContext(ctx, "info", "a", 1)
+	l.Log(ctx, ctxdslog.LevelImportant, "important", "a", 1)
+	l.WarnContext(ctx, "warn", "a", 1)
+	l.ErrorContext(ctx, "error", "a", 1)
+
+	l.With("b", 2).WithGroup("g").With("c", 3).InfoContext(ctx, "grouped",
+		slog.Group("sub", "d", 4), slog.Group("", "e", 5), slog.Attr{})
+
+	assert.Equal(t, `debug: debug {"a":1,"foo":1}
+info: info {"a":1,"foo":1}
+important: important {"a":1,"foo":1}
+warn: warn {"a":1,"foo":1}
+error: error {"a":1,"foo":1}
+info: grouped {"b":2,"foo":1,"g.c":3,"g.e":5,"g.sub.d":4}
+`, lm.String())
+}
+
+func TestHandler_Enabled(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := slog.New(ctxdslog.NewHandler(&lm, nil))
+	ctx := context.Background()
+
+	l.DebugContext(ctx, "debug")
+	l.InfoContext(ctx, "info")
+	l.Log(ctx, ctxdslog.LevelImportant, "important")
+
+	lvl := &slog.LevelVar{}
+	lvl.Set(slog.LevelError)
+
+	l = slog.New(ctxdslog.NewHandler(&lm, &ctxdslog.HandlerOptions{Level: lvl}))
+	l.WarnContext(ctx, "warn")
+	l.Log(ctx, ctxdslog.LevelImportant, "important")
+
+	assert.Equal(t, `info: info null
+important: important null
+important: important null
+`, lm.String())
+}
+
+func TestHandler_Enabled_logger(t *testing.T) {
+	h := ctxdslog.NewHandler(ctxd.NoOpLogger{}, &ctxdslog.HandlerOptions{Level: slog.LevelDebug})
+	ctx := context.Background()
+
+	assert.False(t, h.Enabled(ctx, slog.LevelError))
+	assert.True(t, h.Enabled(ctx, ctxdslog.LevelImportant))
+
+	h = ctxdslog.NewHandler(&ctxd.JSONLogger{}, &ctxdslog.HandlerOptions{Level: slog.LevelDebug})
+	assert.False(t, h.Enabled(ctx, slog.LevelDebug))
+	assert.True(t, h.Enabled(ctx, slog.LevelInfo))
+}
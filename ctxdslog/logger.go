@@ -0,0 +1,137 @@
+//go:build go1.21
+// +build go1.21
+
+package ctxdslog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/bool64/ctxd"
+)
+
+// Logger is a ctxd.Logger backed by *slog.Logger.
+//
+// Fields from context are added to records as attributes before call-site keys and values.
+// Important messages are logged with LevelImportant disregarding handler level.
+// Level from context (see ctxd.WithLevel) takes precedence over handler level.
+type Logger struct {
+	logger *slog.Logger
+
+	// TimeNow returns time of records, time.Now is used if nil.
+	TimeNow func() time.Time
+}
+
+var _ ctxd.Logger = Logger{}
+
+// NewLogger creates ctxd.Logger backed by *slog.Logger, slog.Default() is used if l is nil.
+func NewLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+
+	return Logger{logger: l}
+}
+
+// Enabled checks if messages of level would be logged in context.
+func (l Logger) Enabled(ctx context.Context, level ctxd.Level) bool {
+	if level == ctxd.ImportantLevel {
+		return true
+	}
+
+	if lvl, ok := ctxd.LevelFrom(ctx); ok {
+		return level >= lvl
+	}
+
+	return l.logger.Handler().Enabled(ctx, slogLevel(level))
+}
+
+// Debug logs a message with slog.LevelDebug.
+func (l Logger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, slog.LevelDebug, msg, keysAndValues)
+}
+
+// Info logs a message with slog.LevelInfo.
+func (l Logger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, slog.LevelInfo, msg, keysAndValues)
+}
+
+// Important logs a message with LevelImportant.
+func (l Logger) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, LevelImportant, msg, keysAndValues)
+}
+
+// Warn logs a message with slog.LevelWarn.
+func (l Logger) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, slog.LevelWarn, msg, keysAndValues)
+}
+
+// Error logs a message with slog.LevelError.
+func (l Logger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, slog.LevelError, msg, keysAndValues)
+}
+
+// slogLevel maps ctxd level to slog level.
+func slogLevel(level ctxd.Level) slog.Level {
+	switch level {
+	case ctxd.DebugLevel:
+		return slog.LevelDebug
+	case ctxd.InfoLevel:
+		return slog.LevelInfo
+	case ctxd.WarnLevel:
+		return slog.LevelWarn
+	case ctxd.ErrorLevel:
+		return slog.LevelError
+	default:
+		return LevelImportant
+	}
+}
+
+// SlogLogger returns underlying *slog.Logger.
+func (l Logger) SlogLogger() *slog.Logger {
+	return l.logger
+}
+
+func (l Logger) log(ctx context.Context, level slog.Level, msg string, keysAndValues []interface{}) {
+	h := l.logger.Handler()
+
+	if level != LevelImportant {
+		if l, ok := ctxd.LevelFrom(ctx); ok {
+			if ctxdLevel(level) < l {
+				return
+			}
+		} else if !h.Enabled(ctx, level) {
+			return
+		}
+	}
+
+	var pcs [1]uintptr
+
+	// Skip runtime.Callers, log and Logger method.
+	runtime.Callers(3, pcs[:])
+
+	now := time.Now
+	if l.TimeNow != nil {
+		now = l.TimeNow
+	}
+
+	r := slog.NewRecord(now(), level, msg, pcs[0])
+	r.Add(ctxd.Fields(ctx)...)
+	r.Add(keysAndValues...)
+
+	// ctxd.Logger has no means to report errors.
+	_ = h.Handle(ctx, r)
+}
+
+// ReplaceLevelName is a slog.HandlerOptions.ReplaceAttr function that renders LevelImportant as "IMPORTANT".
+func ReplaceLevelName(_ []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.LevelKey {
+		if lvl, ok := a.Value.Any().(slog.Level); ok && lvl == LevelImportant {
+			a.Value = slog.StringValue("IMPORTANT")
+		}
+	}
+
+	return a
+}
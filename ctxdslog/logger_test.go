@@ -0,0 +1,92 @@
+//go:build go1.21
+// +build go1.21
+
+package ctxdslog_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/ctxd/ctxdslog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	buf := bytes.Buffer{}
+	h := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level:     slog.LevelWarn,
+		AddSource: true,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			if a.Key == slog.SourceKey {
+				s := a.Value.Any().(*slog.Source)
+				s.File = "logger_test.go"
+			}
+
+			return ctxdslog.ReplaceLevelName(groups, a)
+		},
+	})
+
+	l := ctxdslog.NewLogger(slog.New(h))
+	assert.Equal(t, h, l.SlogLogger().Handler())
+
+	ctx := ctxd.AddFields(context.Background(), "foo", 1)
+
+	l.Debug(ctx, "debug", "a", 1)
+	l.Info(ctx, "info", "a", 1)
+	l.Important(ctx, "important", "a", 1)
+	l.Warn(ctx, "warn", "a", 1)
+	l.Error(ctx, "error", "a", 1)
+
+	assert.Equal(t, `level=IMPORTANT source=logger_test.go:44 msg=important foo=1 a=1
+level=WARN source=logger_test.go:45 msg=warn foo=1 a=1
+level=ERROR source=logger_test.go:46 msg=error foo=1 a=1
+`, buf.String())
+}
+
+func TestNewLogger_default(t *testing.T) {
+	assert.Equal(t, slog.Default(), ctxdslog.NewLogger(nil).SlogLogger())
+}
+
+func TestLogger_contextLevel(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxdslog.NewLogger(slog.New(ctxdslog.NewHandler(&lm, nil)))
+
+	l.Debug(ctxd.WithDebug(context.Background()), "debug raised")
+	l.Info(ctxd.WithLevel(context.Background(), ctxd.ErrorLevel), "info lowered")
+	l.Error(ctxd.WithLevel(context.Background(), ctxd.ErrorLevel), "error")
+
+	assert.Equal(t, `debug mode, debug: debug raised null
+error: error null
+`, lm.String())
+}
+
+func TestLogger_Enabled(t *testing.T) {
+	l := ctxdslog.NewLogger(slog.New(ctxdslog.NewHandler(&ctxd.LoggerMock{}, nil)))
+	ctx := context.Background()
+
+	assert.False(t, ctxd.Enabled(ctx, l, ctxd.DebugLevel))
+	assert.True(t, ctxd.Enabled(ctx, l, ctxd.InfoLevel))
+	assert.True(t, ctxd.Enabled(ctxd.WithDebug(ctx), l, ctxd.DebugLevel))
+	assert.False(t, ctxd.Enabled(ctxd.WithLevel(ctx, ctxd.ErrorLevel), l, ctxd.WarnLevel))
+	assert.True(t, ctxd.Enabled(ctxd.WithLevel(ctx, ctxd.ErrorLevel), l, ctxd.ImportantLevel))
+}
+
+func TestLogger_TimeNow(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxdslog.NewLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	l.TimeNow = func() time.Time {
+		return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+
+	l.Info(context.Background(), "hello", "a", 1)
+
+	assert.Equal(t, "time=2020-01-02T03:04:05.000Z level=INFO msg=hello a=1\n", buf.String())
+}
@@ -0,0 +1,49 @@
+package ctxdtest
+
+import "strings"
+
+// diffLines returns line-by-line difference of expected and actual text,
+// removed lines are prefixed with "- ", added lines with "+ " and common lines with two spaces.
+func diffLines(expected, actual string) string {
+	e := strings.Split(strings.TrimSuffix(expected, "\n"), "\n")
+	a := strings.Split(strings.TrimSuffix(actual, "\n"), "\n")
+
+	// lcs[i][j] is a length of the longest common subsequence of e[i:] and a[j:].
+	lcs := make([][]int, len(e)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(a)+1)
+	}
+
+	for i := len(e) - 1; i >= 0; i-- {
+		for j := len(a) - 1; j >= 0; j-- {
+			switch {
+			case e[i] == a[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	b := strings.Builder{}
+	i, j := 0, 0
+
+	for i < len(e) || j < len(a) {
+		switch {
+		case i < len(e) && j < len(a) && e[i] == a[j]:
+			b.WriteString("  " + e[i] + "\n")
+			i++
+			j++
+		case j == len(a) || (i < len(e) && lcs[i+1][j] >= lcs[i][j+1]):
+			b.WriteString("- " + e[i] + "\n")
+			i++
+		default:
+			b.WriteString("+ " + a[j] + "\n")
+			j++
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,2 @@
+// Package ctxdtest provides helpers to test code with contextualized logging.
+package ctxdtest
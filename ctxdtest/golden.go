@@ -0,0 +1,149 @@
+package ctxdtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bool64/ctxd"
+)
+
+// MaskedValue replaces values of masked fields in golden files.
+const MaskedValue = "<masked>"
+
+// UpdateGoldenEnv is an environment variable that enables update of golden files with value "1" or "true".
+const UpdateGoldenEnv = "CTXD_UPDATE_GOLDEN"
+
+// updateGolden checks if golden files should be updated.
+//
+// Flag "update" is not defined by this package to avoid conflicts, but it is respected if test package defines it.
+func updateGolden() bool {
+	if v := os.Getenv(UpdateGoldenEnv); v == "1" || v == "true" {
+		return true
+	}
+
+	f := flag.Lookup("update")
+
+	return f != nil && f.Value.String() == "true"
+}
+
+// AssertGolden compares entries of LoggerMock with golden file and reports difference to tb.
+//
+// Entries are serialized without timestamps, as a JSON array if path has ".json" extension
+// or as lines of "level: message {fields}" otherwise. Fields are ordered by key,
+// values of fields with masked keys are replaced with MaskedValue.
+//
+// Golden file is written instead of comparison if CTXD_UPDATE_GOLDEN=1 environment variable is set,
+// or if test package defines boolean "update" flag and test is run with -update.
+func AssertGolden(tb testing.TB, m *ctxd.LoggerMock, path string, maskedKeys ...string) bool {
+	tb.Helper()
+
+	actual, err := marshalGolden(m.Entries(), strings.HasSuffix(path, ".json"), maskedKeys)
+	if err != nil {
+		tb.Errorf("failed to serialize entries: %v", err)
+
+		return false
+	}
+
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			tb.Errorf("failed to create golden file directory: %v", err)
+
+			return false
+		}
+
+		if err := os.WriteFile(path, actual, 0o600); err != nil {
+			tb.Errorf("failed to write golden file: %v", err)
+
+			return false
+		}
+
+		return true
+	}
+
+	expected, err := os.ReadFile(path) //nolint:gosec // Path is controlled by test.
+	if err != nil {
+		tb.Errorf("failed to read golden file, set CTXD_UPDATE_GOLDEN=1 to create it: %v", err)
+
+		return false
+	}
+
+	if bytes.Equal(expected, actual) {
+		return true
+	}
+
+	tb.Errorf("logged entries do not match golden file %s, set CTXD_UPDATE_GOLDEN=1 to update it:\n%s",
+		path, diffLines(string(expected), string(actual)))
+
+	return false
+}
+
+type goldenEntry struct {
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+func marshalGolden(entries ctxd.LoggedEntries, asJSON bool, maskedKeys []string) ([]byte, error) {
+	res := make([]goldenEntry, 0, len(entries))
+
+	for _, e := range entries {
+		res = append(res, goldenEntry{Level: e.Level, Message: e.Message, Data: maskFields(e.Data, maskedKeys)})
+	}
+
+	b := bytes.Buffer{}
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+
+	if asJSON {
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(res); err != nil {
+			return nil, err
+		}
+
+		return b.Bytes(), nil
+	}
+
+	for _, e := range res {
+		b.WriteString(e.Level + ": " + e.Message + " ")
+
+		// Encoder terminates data with a new line.
+		if err := enc.Encode(e.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// maskFields returns a copy of data with values of masked keys replaced, data is returned as is if nothing is masked.
+func maskFields(data map[string]interface{}, maskedKeys []string) map[string]interface{} {
+	var res map[string]interface{}
+
+	for _, k := range maskedKeys {
+		if _, ok := data[k]; !ok {
+			continue
+		}
+
+		if res == nil {
+			res = make(map[string]interface{}, len(data))
+
+			for dk, dv := range data {
+				res[dk] = dv
+			}
+		}
+
+		res[k] = MaskedValue
+	}
+
+	if res == nil {
+		return data
+	}
+
+	return res
+}
@@ -0,0 +1,108 @@
+package ctxdtest_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/ctxd/ctxdtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logEntries(m *ctxd.LoggerMock) {
+	ctx := ctxd.AddFields(context.Background(), "request.id", "a1b2c3", "user", "alice")
+
+	m.Info(ctx, "request started", "path", "/foo")
+	m.Warn(ctx, "slow query", "duration", 123*time.Millisecond, "rows", 10)
+	m.Error(ctx, "request failed", "status", 500)
+}
+
+func TestAssertGolden(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	logEntries(&m)
+
+	ctxdtest.AssertGolden(t, &m, "testdata/logs.txt", "request.id", "duration")
+	ctxdtest.AssertGolden(t, &m, "testdata/logs.json", "request.id", "duration")
+}
+
+func TestAssertGolden_mismatch(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	logEntries(&m)
+	m.Info(context.Background(), "unexpected")
+
+	tb := &recordingTB{TB: t}
+
+	assert.False(t, ctxdtest.AssertGolden(tb, &m, "testdata/logs.txt", "request.id", "duration"))
+	require.Len(t, tb.errors, 1)
+	assert.Equal(t, `logged entries do not match golden file testdata/logs.txt, set CTXD_UPDATE_GOLDEN=1 to update it:
+  info: request started {"path":"/foo","request.id":"<masked>","user":"alice"}
+  warn: slow query {"duration":"<masked>","request.id":"<masked>","rows":10,"user":"alice"}
+  error: request failed {"request.id":"<masked>","status":500,"user":"alice"}
++ info: unexpected null
+`, tb.errors[0])
+
+	tb = &recordingTB{TB: t}
+
+	assert.False(t, ctxdtest.AssertGolden(tb, &m, "testdata/missing.txt"))
+	require.Len(t, tb.errors, 1)
+	assert.Contains(t, tb.errors[0], "failed to read golden file, set CTXD_UPDATE_GOLDEN=1 to create it")
+}
+
+func TestAssertGolden_update(t *testing.T) {
+	prev, isSet := os.LookupEnv(ctxdtest.UpdateGoldenEnv)
+	require.NoError(t, os.Setenv(ctxdtest.UpdateGoldenEnv, "1"))
+
+	defer func() {
+		if isSet {
+			require.NoError(t, os.Setenv(ctxdtest.UpdateGoldenEnv, prev))
+		} else {
+			require.NoError(t, os.Unsetenv(ctxdtest.UpdateGoldenEnv))
+		}
+	}()
+
+	m := ctxd.LoggerMock{}
+	m.Info(context.Background(), "hello", "k", "v")
+
+	path := filepath.Join(t.TempDir(), "sub", "logs.txt")
+
+	assert.True(t, ctxdtest.AssertGolden(t, &m, path))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `info: hello {"k":"v"}`+"\n", string(b))
+}
+
+// update is defined by test package, it is respected by AssertGolden.
+var update = flag.Bool("update", false, "update golden files")
+
+func TestAssertGolden_updateFlag(t *testing.T) {
+	require.NoError(t, flag.Set("update", "true"))
+
+	defer func() {
+		require.NoError(t, flag.Set("update", "false"))
+	}()
+
+	assert.True(t, *update)
+
+	m := ctxd.LoggerMock{}
+	m.Info(context.Background(), "hello")
+
+	path := filepath.Join(t.TempDir(), "logs.json")
+
+	assert.True(t, ctxdtest.AssertGolden(t, &m, path))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `[
+  {
+    "level": "info",
+    "message": "hello"
+  }
+]
+`, string(b))
+}
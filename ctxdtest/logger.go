@@ -0,0 +1,130 @@
+package ctxdtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bool64/ctxd"
+)
+
+// Logger writes entries to test log with testing.TB.Log.
+//
+// Entry is formatted as level, message and fields sorted by key,
+// fields from context (see ctxd.Fields) are merged with keys and values of call.
+// Entries logged after test has finished are discarded.
+type Logger struct {
+	// FailOnError marks test as failed on Error messages.
+	FailOnError bool
+
+	tb       testing.TB
+	mu       sync.RWMutex
+	finished bool
+}
+
+var _ ctxd.Logger = &Logger{}
+
+// NewLogger creates a logger for a test.
+func NewLogger(tb testing.TB) *Logger {
+	tb.Helper()
+
+	l := &Logger{tb: tb}
+
+	tb.Cleanup(func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.finished = true
+	})
+
+	return l
+}
+
+// Debug logs a message.
+func (l *Logger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.tb.Helper()
+	l.log(ctx, "DEBUG", msg, keysAndValues, false)
+}
+
+// Info logs a message.
+func (l *Logger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.tb.Helper()
+	l.log(ctx, "INFO", msg, keysAndValues, false)
+}
+
+// Important logs a message.
+func (l *Logger) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.tb.Helper()
+	l.log(ctx, "IMPORTANT", msg, keysAndValues, false)
+}
+
+// Warn logs a message.
+func (l *Logger) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.tb.Helper()
+	l.log(ctx, "WARN", msg, keysAndValues, false)
+}
+
+// Error logs a message and fails the test if FailOnError is enabled.
+func (l *Logger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.tb.Helper()
+	l.log(ctx, "ERROR", msg, keysAndValues, l.FailOnError)
+}
+
+func (l *Logger) log(ctx context.Context, level, msg string, keysAndValues []interface{}, fail bool) {
+	l.tb.Helper()
+
+	line := formatEntry(level, msg, ctxd.Tuples(append(ctxd.Fields(ctx), keysAndValues...)).Fields())
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.finished {
+		return
+	}
+
+	if fail {
+		l.tb.Error(line)
+	} else {
+		l.tb.Log(line)
+	}
+}
+
+func formatEntry(level, msg string, fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	b := strings.Builder{}
+	b.WriteString(level)
+	b.WriteString(": ")
+	b.WriteString(msg)
+
+	for _, k := range keys {
+		b.WriteString(" ")
+		b.WriteString(k)
+		b.WriteString("=")
+		b.WriteString(formatValue(fields[k]))
+	}
+
+	return b.String()
+}
+
+func formatValue(v interface{}) string {
+	s, ok := v.(string)
+	if !ok {
+		s = fmt.Sprintf("%+v", v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\n\t") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
@@ -0,0 +1,87 @@
+package ctxdtest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/ctxd/ctxdtest"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingTB struct {
+	testing.TB
+
+	logs     []string
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Log(args ...interface{}) {
+	r.logs = append(r.logs, fmt.Sprint(args...))
+}
+
+func (r *recordingTB) Error(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recordingTB) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestLogger(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	l := ctxdtest.NewLogger(tb)
+	ctx := ctxd.AddFields(context.Background(), "foo", "bar baz", "n", 1)
+
+	l.Debug(ctx, "debug", "n", 2)
+	l.Info(ctx, "info", "empty", "")
+	l.Important(ctx, "important")
+	l.Warn(context.Background(), "warn")
+	l.Error(ctx, "error")
+
+	l.FailOnError = true
+	l.Error(ctx, "failed", "err", ctxd.NewError(ctx, "oops"))
+
+	tb.finish()
+
+	l.Error(ctx, "dropped after finish")
+
+	assert.Equal(t, []string{
+		`DEBUG: debug foo="bar baz" n=2`,
+		`INFO: info empty="" foo="bar baz" n=1`,
+		`IMPORTANT: important foo="bar baz" n=1`,
+		`WARN: warn`,
+		`ERROR: error foo="bar baz" n=1`,
+	}, tb.logs)
+	assert.Equal(t, []string{`ERROR: failed err=oops foo="bar baz" n=1`}, tb.errors)
+}
+
+func TestNewLogger(t *testing.T) {
+	var l *ctxdtest.Logger
+
+	t.Run("sub", func(t *testing.T) {
+		t.Parallel()
+
+		l = ctxdtest.NewLogger(t)
+		l.Info(context.Background(), "hello", "k", "v")
+	})
+
+	t.Cleanup(func() {
+		// Logging after subtest has finished does not panic.
+		l.Info(context.Background(), "after finish")
+	})
+}
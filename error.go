@@ -15,21 +15,30 @@ type LogFunc func(ctx context.Context, msg string, keysAndValues ...interface{})
 //
 // If err is nil, LogError produces no operation.
 // LogError function matches Logger methods, e.g. Error.
+// Call stack of error (see StackTracer) is added as "error.stack_trace" field, this key can not be customized.
 func LogError(ctx context.Context, err error, l LogFunc) {
 	if err == nil {
 		return
 	}
 
-	var se StructuredError
+	var (
+		se StructuredError
+		st StackTracer
+		kv []interface{}
+	)
+
+	if errors.As(err, &st) {
+		kv = []interface{}{defaultFieldNames.ErrorStackTrace, st.StackTrace().String()}
+	}
 
 	if errors.As(err, &se) {
 		// Discarding keys and values from context as error already has full set of fields prepared on invocation.
-		l(ClearFields(ctx), se.Error(), se.Tuples()...)
+		l(ClearFields(ctx), se.Error(), append(se.Tuples(), kv...)...)
 
 		return
 	}
 
-	l(ctx, err.Error())
+	l(ctx, err.Error(), kv...)
 }
 
 // StructuredError defines error with message and data.
@@ -61,6 +70,7 @@ func (we wrappedError) Error() string {
 //
 // If err is nil, WrapError returns nil.
 // LogError fields from context are also added to error structured data.
+// Call stack is captured if enabled with CaptureErrorStack and err does not have it yet.
 func WrapError(ctx context.Context, err error, message string, keysAndValues ...interface{}) error {
 	if err == nil {
 		return nil
@@ -73,6 +83,8 @@ func WrapError(ctx context.Context, err error, message string, keysAndValues ...
 		}
 	}
 
+	err = maybeWithStack(err)
+
 	se, ok := newError(ctx, err, keysAndValues...)
 	if ok {
 		return wrappedStructuredError{
@@ -86,11 +98,22 @@ func WrapError(ctx context.Context, err error, message string, keysAndValues ...
 // NewError creates error with optional structured data.
 //
 // LogError fields from context are also added to error structured data.
+// Call stack is captured if enabled with CaptureErrorStack.
 func NewError(ctx context.Context, message string, keysAndValues ...interface{}) error {
 	//nolint:goerr113 // Static errors can be used with WrapError.
-	err := errors.New(message)
+	err := maybeWithStack(errors.New(message))
 
 	se, ok := newError(ctx, err, keysAndValues...)
+
+	// Errors of NewError are not unwrapped, stack is exposed without revealing underlying error.
+	if st, isStack := err.(stackError); isStack { //nolint:errorlint // Error is created above.
+		if ok {
+			return tracedStructuredError{structuredError: se, stack: st.stack}
+		}
+
+		return tracedError{err: st}
+	}
+
 	if ok {
 		return se
 	}
@@ -115,6 +138,35 @@ func (wse wrappedStructuredError) Unwrap() error {
 	return wse.err
 }
 
+type tracedStructuredError struct {
+	structuredError
+	stack StackTrace
+}
+
+// StackTrace returns captured call stack.
+func (tse tracedStructuredError) StackTrace() StackTrace {
+	return tse.stack
+}
+
+type tracedError struct {
+	err stackError
+}
+
+// Error returns message.
+func (te tracedError) Error() string {
+	return te.err.Error()
+}
+
+// StackTrace returns captured call stack.
+func (te tracedError) StackTrace() StackTrace {
+	return te.err.stack
+}
+
+// Format implements fmt.Formatter, %+v prints message with call stack.
+func (te tracedError) Format(s fmt.State, verb rune) {
+	te.err.Format(s, verb)
+}
+
 // Fields creates a map from key-value pairs.
 func (t Tuples) Fields() map[string]interface{} {
 	if len(t) == 0 {
@@ -180,6 +232,12 @@ func (se structuredError) String() string {
 	return err
 }
 
+// Format implements fmt.Formatter, %+v prints message with call stack if available.
+func (se structuredError) Format(s fmt.State, verb rune) {
+	// Message of structured error is the message of underlying error, that may have a call stack.
+	formatError(se.err, s, verb)
+}
+
 // Error returns message of error.
 func (se structuredError) Error() string {
 	return se.err.Error()
@@ -203,13 +261,15 @@ func newError(ctx context.Context, err error, keysAndValues ...interface{}) (str
 	}
 
 	ctxFields = Fields(ctx)
+	caller := errorCaller(tuples)
 
-	if len(tuples)+len(ctxFields) > 0 {
-		kv = make([]interface{}, 0, len(kv)+len(tuples)+len(ctxFields))
+	if len(tuples)+len(ctxFields)+len(caller) > 0 {
+		kv = make([]interface{}, 0, len(kv)+len(tuples)+len(ctxFields)+len(caller))
 
 		kv = append(kv, tuples...)
 		kv = append(kv, keysAndValues...)
 		kv = append(kv, ctxFields...)
+		kv = append(kv, caller...)
 	}
 
 	if len(kv) > 1 {
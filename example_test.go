@@ -121,3 +121,34 @@ func ExampleLabeledError() {
 	// err as &se: true
 	// err as value: miserably
 }
+
+func ExampleChainLogger() {
+	lm := ctxd.LoggerMock{}
+
+	// Middleware adds a field to every entry.
+	withVersion := func(next ctxd.Handler) ctxd.Handler {
+		return ctxd.HandlerFunc(func(e ctxd.Entry) {
+			e.Ctx = ctxd.AddFields(e.Ctx, "version", "1.2.3")
+			next.Handle(e)
+		})
+	}
+
+	// Middleware discards debug entries.
+	noDebug := func(next ctxd.Handler) ctxd.Handler {
+		return ctxd.HandlerFunc(func(e ctxd.Entry) {
+			if e.Level != ctxd.DebugLevel {
+				next.Handle(e)
+			}
+		})
+	}
+
+	logger := ctxd.ChainLogger(&lm, noDebug, withVersion)
+
+	logger.Debug(context.Background(), "discarded")
+	logger.Info(context.Background(), "hello", "foo", "bar")
+
+	fmt.Print(lm.String())
+
+	// Output:
+	// info: hello {"foo":"bar","version":"1.2.3"}
+}
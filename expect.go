@@ -0,0 +1,156 @@
+package ctxd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// TestingTB is a subset of testing.TB used by StrictLoggerMock.
+type TestingTB interface {
+	TestingT
+	Cleanup(f func())
+}
+
+// StrictLoggerMock is a LoggerMock that checks logged entries against expectations.
+//
+// Entries that do not match expectations and unmet expectations are reported when test finishes.
+type StrictLoggerMock struct {
+	LoggerMock
+
+	// Ordered requires entries to be logged in order of expectations.
+	Ordered bool
+
+	t            TestingT
+	mu           sync.Mutex
+	expectations []*Expectation
+}
+
+// NewStrictLoggerMock creates StrictLoggerMock that checks expectations in t.Cleanup.
+func NewStrictLoggerMock(t TestingTB) *StrictLoggerMock {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	m := &StrictLoggerMock{t: t}
+
+	t.Cleanup(func() {
+		m.AssertExpectations()
+	})
+
+	return m
+}
+
+// Expectation describes expected entry of StrictLoggerMock.
+type Expectation struct {
+	level  Level
+	msg    string
+	fields map[string]interface{}
+	times  int
+}
+
+// Expect adds an expectation of entry with level and message, logged once by default.
+func (m *StrictLoggerMock) Expect(level Level, msg string) *Expectation {
+	e := &Expectation{level: level, msg: msg, times: 1}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = append(m.expectations, e)
+
+	return e
+}
+
+// WithFields requires entry to have fields defined by loosely-typed key-value pairs, other fields are ignored.
+func (e *Expectation) WithFields(keysAndValues ...interface{}) *Expectation {
+	e.fields = Tuples(keysAndValues).Fields()
+
+	return e
+}
+
+// Times sets number of entries expected to match.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+
+	return e
+}
+
+// String returns expectation description.
+func (e *Expectation) String() string {
+	return e.level.String() + ": " + e.msg + " " + marshalFields(e.fields)
+}
+
+func (e *Expectation) matches(entry LoggedEntry) bool {
+	return entry.matches(e.level.String(), e.msg, e.fields)
+}
+
+// AssertExpectations reports unexpected entries and unmet expectations, it returns true if there are none.
+//
+// It is called automatically in cleanup of test for mock created with NewStrictLoggerMock.
+func (m *StrictLoggerMock) AssertExpectations() bool {
+	if h, ok := m.t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var (
+		counts     = make([]int, len(m.expectations))
+		unexpected []string
+		pos        int
+	)
+
+	for _, entry := range m.Entries() {
+		matched := false
+
+		if m.Ordered {
+			// Skipping expectations that are already satisfied.
+			for pos < len(m.expectations) && counts[pos] >= m.expectations[pos].times {
+				pos++
+			}
+
+			if pos < len(m.expectations) && m.expectations[pos].matches(entry) {
+				counts[pos]++
+				matched = true
+			}
+		} else {
+			for i, e := range m.expectations {
+				if counts[i] < e.times && e.matches(entry) {
+					counts[i]++
+					matched = true
+
+					break
+				}
+			}
+		}
+
+		if !matched {
+			unexpected = append(unexpected, LoggedEntries{entry}.String())
+		}
+	}
+
+	ok := true
+
+	if len(unexpected) > 0 {
+		ok = false
+
+		m.t.Errorf("unexpected entries logged:\n%s", strings.Join(unexpected, ""))
+	}
+
+	var unmet []string
+
+	for i, e := range m.expectations {
+		if counts[i] != e.times {
+			unmet = append(unmet, fmt.Sprintf("%s (expected %d, logged %d)\n", e, e.times, counts[i]))
+		}
+	}
+
+	if len(unmet) > 0 {
+		ok = false
+
+		m.t.Errorf("expectations not met:\n%s", strings.Join(unmet, ""))
+	}
+
+	return ok
+}
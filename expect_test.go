@@ -0,0 +1,97 @@
+package ctxd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+)
+
+type cleanupT struct {
+	recordingT
+
+	cleanups []func()
+}
+
+func (c *cleanupT) Cleanup(f func()) {
+	c.cleanups = append(c.cleanups, f)
+}
+
+func (c *cleanupT) finish() {
+	for i := len(c.cleanups) - 1; i >= 0; i-- {
+		c.cleanups[i]()
+	}
+}
+
+func TestNewStrictLoggerMock(t *testing.T) {
+	m := ctxd.NewStrictLoggerMock(t)
+	ctx := ctxd.AddFields(context.Background(), "user", "alice")
+
+	m.Expect(ctxd.InfoLevel, "hello").WithFields("user", "alice")
+	m.Expect(ctxd.WarnLevel, "retry").Times(2)
+
+	m.Warn(ctx, "retry", "attempt", 1)
+	m.Info(ctx, "hello", "k", "v")
+	m.Warn(ctx, "retry", "attempt", 2)
+}
+
+func TestStrictLoggerMock_unordered(t *testing.T) {
+	ct := &cleanupT{}
+	m := ctxd.NewStrictLoggerMock(ct)
+	ctx := context.Background()
+
+	m.Expect(ctxd.InfoLevel, "hello").WithFields("user", "alice")
+	m.Expect(ctxd.WarnLevel, "retry").Times(2)
+	m.Expect(ctxd.ErrorLevel, "failed")
+
+	m.Info(ctx, "hello", "user", "bob")
+	m.Warn(ctx, "retry")
+	m.Debug(ctx, "debug")
+	m.Error(ctx, "failed")
+
+	assert.Empty(t, ct.errors)
+
+	ct.finish()
+
+	assert.Equal(t, []string{
+		"unexpected entries logged:\n" +
+			"info: hello {\"user\":\"bob\"}\n" +
+			"debug: debug null\n",
+		"expectations not met:\n" +
+			"info: hello {\"user\":\"alice\"} (expected 1, logged 0)\n" +
+			"warn: retry null (expected 2, logged 1)\n",
+	}, ct.errors)
+}
+
+func TestStrictLoggerMock_ordered(t *testing.T) {
+	ct := &cleanupT{}
+	m := ctxd.NewStrictLoggerMock(ct)
+	m.Ordered = true
+	ctx := context.Background()
+
+	m.Expect(ctxd.InfoLevel, "first")
+	m.Expect(ctxd.InfoLevel, "second").Times(2)
+	m.Expect(ctxd.InfoLevel, "third")
+
+	m.Info(ctx, "first")
+	m.Info(ctx, "second")
+	m.Info(ctx, "second")
+	m.Info(ctx, "third")
+
+	assert.True(t, m.AssertExpectations())
+
+	m.Reset()
+
+	m.Info(ctx, "second")
+	m.Info(ctx, "first")
+	m.Info(ctx, "second")
+	m.Info(ctx, "second")
+	m.Info(ctx, "third")
+
+	assert.False(t, m.AssertExpectations())
+	assert.Equal(t, []string{
+		"unexpected entries logged:\n" +
+			"info: second null\n",
+	}, ct.errors)
+}
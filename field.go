@@ -1,8 +1,11 @@
 package ctxd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sync"
 )
 
 // DeferredJSON postpones log field processing, suitable for debug logging.
@@ -20,3 +23,117 @@ func (d DeferredString) String() string {
 func (d DeferredJSON) MarshalJSON() ([]byte, error) {
 	return json.Marshal(d())
 }
+
+type field struct {
+	key   string
+	value interface{}
+}
+
+var (
+	fieldsPool = sync.Pool{
+		New: func() interface{} {
+			f := make([]field, 0, 16)
+
+			return &f
+		},
+	}
+
+	bufPool = sync.Pool{
+		New: func() interface{} {
+			b := make([]byte, 0, 1024)
+
+			return &b
+		},
+	}
+)
+
+// acquireFields returns pooled fields merged from context and call-site keys and values.
+func acquireFields(ctx context.Context, keysAndValues []interface{}) *[]field {
+	fp := fieldsPool.Get().(*[]field) //nolint:errcheck // Pool only contains *[]field.
+	*fp = mergeFields((*fp)[:0], Fields(ctx), keysAndValues)
+
+	return fp
+}
+
+func releaseFields(fp *[]field) {
+	fields := *fp
+
+	for i := range fields {
+		fields[i] = field{}
+	}
+
+	*fp = fields[:0]
+	fieldsPool.Put(fp)
+}
+
+func acquireBuf() *[]byte {
+	bp := bufPool.Get().(*[]byte) //nolint:errcheck // Pool only contains *[]byte.
+	*bp = (*bp)[:0]
+
+	return bp
+}
+
+func releaseBuf(bp *[]byte) {
+	*bp = (*bp)[:0]
+	bufPool.Put(bp)
+}
+
+// mergeFields appends fields from loosely-typed key-value pairs to dst.
+//
+// Values of duplicate keys are replaced in place, malformed pairs are collected
+// under "malformedFields" key similarly to Tuples.Fields, malformed pairs of all slices are kept.
+func mergeFields(dst []field, keysAndValues ...[]interface{}) []field {
+	for _, kv := range keysAndValues {
+		for i := 0; i < len(kv); i += 2 {
+			key, ok := kv[i].(string)
+			if !ok || key == "" || i+1 == len(kv) {
+				dst = appendMalformed(dst, kv[i:])
+
+				break
+			}
+
+			dst = setField(dst, key, kv[i+1])
+		}
+	}
+
+	return dst
+}
+
+// appendMalformed adds malformed pairs to "malformedFields" key, values of previous slices are preserved.
+func appendMalformed(dst []field, tail []interface{}) []field {
+	for i := range dst {
+		if dst[i].key != "malformedFields" {
+			continue
+		}
+
+		if prev, ok := dst[i].value.([]interface{}); ok {
+			// New slice is allocated to avoid modification of caller's backing array.
+			merged := make([]interface{}, 0, len(prev)+len(tail))
+			merged = append(merged, prev...)
+			dst[i].value = append(merged, tail...)
+
+			return dst
+		}
+	}
+
+	return setField(dst, "malformedFields", tail)
+}
+
+func setField(dst []field, key string, value interface{}) []field {
+	for i := range dst {
+		if dst[i].key == key {
+			dst[i].value = value
+
+			return dst
+		}
+	}
+
+	return append(dst, field{key: key, value: value})
+}
+
+// isNilPointer checks if v is a typed nil pointer, calling methods of such value may panic.
+func isNilPointer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
@@ -0,0 +1,263 @@
+package ctxd
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// FlightRecordedAtKey is a field with original time of an entry flushed by flight recorder.
+const FlightRecordedAtKey = "log.recorded_at"
+
+// FlightRecorderConfig defines LoggerWithFlightRecorder behavior.
+type FlightRecorderConfig struct {
+	// Size is a maximum number of entries kept in a buffer, default 100.
+	Size int
+
+	// MaxBytes is an approximate maximum size of entries kept in a buffer, default 64 KiB.
+	//
+	// Size of entry is estimated from message and keys and values of the call: strings and []byte by length,
+	// slices and maps by number of elements, other values (including errors and fmt.Stringer) by size of their type,
+	// so that values are not formatted for entries that are likely to be discarded.
+	// Memory referenced by nested pointers and elements, and context of entry (including its fields) are not counted.
+	MaxBytes int
+
+	// Level is a minimal level of messages that are passed through, Debug and Info messages below it are buffered.
+	//
+	// If nil, messages are buffered when underlying logger reports their level as disabled (see Enabled).
+	// Loggers that do not implement LevelEnabler are considered enabled for all levels, so nothing is buffered
+	// with them unless Level is set. Underlying logger should allow debug messages to receive flushed entries.
+	Level *AtomicLevel
+
+	// Global enables a shared buffer for entries logged with context without flight recorder (see WithFlightRecorder).
+	// Entries of such contexts are discarded if disabled.
+	Global bool
+
+	// TimeNow returns time of buffered entries, time.Now is used if nil.
+	TimeNow func() time.Time
+}
+
+type flightRecorderCtxKey struct{}
+
+// WithFlightRecorder returns context with a buffer of recent entries for LoggerWithFlightRecorder.
+//
+// Buffer is scoped to context, e.g. to a request, and is shared by derived contexts.
+func WithFlightRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, flightRecorderCtxKey{}, &flightBuffer{})
+}
+
+// LoggerWithFlightRecorder instruments contextualized logger with a flight recorder of recent entries.
+//
+// Debug and Info messages below FlightRecorderConfig.Level, or disabled in underlying logger if Level is nil
+// (see Enabled), are kept in a buffer of context (see WithFlightRecorder) instead of being discarded.
+// When Error message is logged with such context, buffered entries are flushed to underlying logger
+// with their original levels and debug level enabled in context (see WithLevel),
+// they have an additional "log.recorded_at" field with original time of entry.
+//
+// Other messages are passed through without buffering.
+//
+// Without Level, flight recorder has no effect with loggers that do not implement LevelEnabler.
+func LoggerWithFlightRecorder(logger Logger, cfg FlightRecorderConfig) Logger {
+	if cfg.Size <= 0 {
+		cfg.Size = 100
+	}
+
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = 64 * 1024
+	}
+
+	r := &flightRecorder{
+		logger: logger,
+		cfg:    cfg,
+	}
+
+	if cfg.Global {
+		r.global = &flightBuffer{}
+	}
+
+	return r
+}
+
+type flightEntry struct {
+	ctx           context.Context //nolint:containedctx // Context is stored for deferred logging.
+	time          time.Time
+	level         Level
+	msg           string
+	keysAndValues []interface{}
+	size          int
+}
+
+// flightBuffer is a bounded queue of entries, oldest entries are evicted first.
+type flightBuffer struct {
+	mu      sync.Mutex
+	entries []flightEntry
+	bytes   int
+}
+
+func (b *flightBuffer) push(e flightEntry, cfg FlightRecorderConfig) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Entry that exceeds the limit alone is discarded without evicting others.
+	if e.size > cfg.MaxBytes {
+		return
+	}
+
+	for len(b.entries) > 0 && (len(b.entries) >= cfg.Size || b.bytes+e.size > cfg.MaxBytes) {
+		b.bytes -= b.entries[0].size
+		b.entries[0] = flightEntry{}
+		b.entries = b.entries[1:]
+	}
+
+	b.entries = append(b.entries, e)
+	b.bytes += e.size
+}
+
+// drain returns buffered entries in order of logging and empties buffer.
+func (b *flightBuffer) drain() []flightEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	res := b.entries
+	b.entries = nil
+	b.bytes = 0
+
+	return res
+}
+
+type flightRecorder struct {
+	logger Logger
+	cfg    FlightRecorderConfig
+	global *flightBuffer
+}
+
+func (r *flightRecorder) buffer(ctx context.Context) *flightBuffer {
+	if b, ok := ctx.Value(flightRecorderCtxKey{}).(*flightBuffer); ok {
+		return b
+	}
+
+	return r.global
+}
+
+// passed checks if level is allowed by config or by underlying logger.
+func (r *flightRecorder) passed(ctx context.Context, level Level) bool {
+	if r.cfg.Level != nil {
+		return levelEnabled(ctx, level, r.cfg.Level.Level())
+	}
+
+	return Enabled(ctx, r.logger, level)
+}
+
+// record buffers entry and returns true if level is not passed through.
+func (r *flightRecorder) record(ctx context.Context, level Level, msg string, keysAndValues []interface{}) bool {
+	if r.passed(ctx, level) {
+		return false
+	}
+
+	b := r.buffer(ctx)
+	if b == nil {
+		return true
+	}
+
+	size := len(msg)
+
+	for _, v := range keysAndValues {
+		size += estimateSize(v)
+	}
+
+	b.push(flightEntry{
+		ctx:           detachedContext{parent: ctx},
+		time:          timeNow(r.cfg.TimeNow),
+		level:         level,
+		msg:           msg,
+		keysAndValues: append([]interface{}(nil), keysAndValues...),
+		size:          size,
+	}, r.cfg)
+
+	return true
+}
+
+func (r *flightRecorder) flush(ctx context.Context) {
+	b := r.buffer(ctx)
+	if b == nil {
+		return
+	}
+
+	for _, e := range b.drain() {
+		Log(WithLevel(e.ctx, DebugLevel), r.logger, e.level, e.msg, append(e.keysAndValues, FlightRecordedAtKey, e.time)...)
+	}
+}
+
+func (r *flightRecorder) Enabled(ctx context.Context, level Level) bool {
+	if level <= InfoLevel {
+		if r.buffer(ctx) != nil {
+			return true
+		}
+
+		if !r.passed(ctx, level) {
+			return false
+		}
+	}
+
+	return Enabled(ctx, r.logger, level)
+}
+
+func (r *flightRecorder) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if !r.record(ctx, DebugLevel, msg, keysAndValues) {
+		r.logger.Debug(ctx, msg, keysAndValues...)
+	}
+}
+
+func (r *flightRecorder) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if !r.record(ctx, InfoLevel, msg, keysAndValues) {
+		r.logger.Info(ctx, msg, keysAndValues...)
+	}
+}
+
+func (r *flightRecorder) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	r.logger.Important(ctx, msg, keysAndValues...)
+}
+
+func (r *flightRecorder) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	r.logger.Warn(ctx, msg, keysAndValues...)
+}
+
+func (r *flightRecorder) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	r.flush(ctx)
+	r.logger.Error(ctx, msg, keysAndValues...)
+}
+
+// flightSlotSize is an estimated size of an interface value.
+const flightSlotSize = 16
+
+// estimateSize returns approximate size of memory retained by v.
+func estimateSize(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return flightSlotSize
+	case string:
+		return flightSlotSize + len(v)
+	case []byte:
+		return flightSlotSize + len(v)
+	case DeferredJSON, DeferredString:
+		// Deferred values are not evaluated to keep them cheap.
+		return flightSlotSize
+	}
+
+	rv := reflect.ValueOf(v)
+	size := flightSlotSize + int(rv.Type().Size())
+
+	switch rv.Kind() { //nolint:exhaustive // Other kinds are estimated by type size.
+	case reflect.Slice:
+		size += rv.Len() * int(rv.Type().Elem().Size())
+	case reflect.Map:
+		size += rv.Len() * int(rv.Type().Key().Size()+rv.Type().Elem().Size())
+	case reflect.Ptr:
+		if !rv.IsNil() {
+			size += int(rv.Type().Elem().Size())
+		}
+	}
+
+	return size
+}
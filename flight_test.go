@@ -0,0 +1,158 @@
+package ctxd_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerWithFlightRecorder(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithFlightRecorder(
+		ctxd.LoggerWithLevel(&m, ctxd.NewAtomicLevel(ctxd.WarnLevel)),
+		ctxd.FlightRecorderConfig{Size: 2},
+	)
+
+	ctx := ctxd.WithFlightRecorder(ctxd.AddFields(context.Background(), "req", 1))
+	other := ctxd.WithFlightRecorder(context.Background())
+
+	assert.True(t, ctxd.Enabled(ctx, l, ctxd.DebugLevel))
+	assert.False(t, ctxd.Enabled(context.Background(), l, ctxd.DebugLevel))
+
+	l.Debug(ctx, "dropped by size")
+	l.Debug(ctx, "debug 1", "k", 1)
+	l.Info(ctx, "info")
+	l.Debug(ctx, "debug 2", "k", 2)
+	l.Debug(other, "other")
+	l.Debug(context.Background(), "discarded")
+	l.Error(ctx, "failed")
+	l.Error(ctx, "failed again")
+
+	require.Len(t, m.LoggedEntries, 4)
+
+	for _, i := range []int{0, 1} {
+		assert.IsType(t, time.Time{}, m.LoggedEntries[i].Data[ctxd.FlightRecordedAtKey])
+		delete(m.LoggedEntries[i].Data, ctxd.FlightRecordedAtKey)
+	}
+
+	assert.Equal(t, "info", m.LoggedEntries[0].Level)
+	assert.Equal(t, "info", m.LoggedEntries[0].Message)
+	assert.Equal(t, map[string]interface{}{"req": 1}, m.LoggedEntries[0].Data)
+	assert.Equal(t, "debug", m.LoggedEntries[1].Level)
+	assert.Equal(t, "debug 2", m.LoggedEntries[1].Message)
+	assert.Equal(t, map[string]interface{}{"req": 1, "k": 2}, m.LoggedEntries[1].Data)
+	assert.Equal(t, "failed", m.LoggedEntries[2].Message)
+	assert.Equal(t, "failed again", m.LoggedEntries[3].Message)
+}
+
+func TestLoggerWithFlightRecorder_LogError(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithFlightRecorder(
+		ctxd.LoggerWithLevel(&m, ctxd.NewAtomicLevel(ctxd.WarnLevel)),
+		ctxd.FlightRecorderConfig{Global: true, MaxBytes: 100},
+	)
+
+	ctx := context.Background()
+
+	l.Info(ctx, "too large", "v", strings.Repeat("a", 100))
+	l.Info(ctx, "evicted by size", "v", strings.Repeat("a", 50))
+	l.Info(ctx, "kept", "v", strings.Repeat("a", 40))
+	l.Warn(ctx, "warn")
+
+	ctxd.LogError(ctx, ctxd.WrapError(ctx, errors.New("oops"), "failed", "k", "v"), l.Error)
+
+	require.Len(t, m.LoggedEntries, 3)
+	assert.Equal(t, "warn", m.LoggedEntries[0].Message)
+	assert.Equal(t, "kept", m.LoggedEntries[1].Message)
+	assert.Equal(t, "failed: oops", m.LoggedEntries[2].Message)
+}
+
+func TestLoggerWithFlightRecorder_passThrough(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithFlightRecorder(&m, ctxd.FlightRecorderConfig{Global: true})
+	ctx := context.Background()
+
+	l.Debug(ctx, "debug")
+	l.Info(ctx, "info")
+	l.Important(ctx, "important")
+	l.Error(ctx, "error")
+
+	assert.Equal(t, `debug: debug null
+info: info null
+important: important null
+error: error null
+`, m.String())
+}
+
+func TestLoggerWithFlightRecorder_MaxBytes(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithFlightRecorder(
+		ctxd.LoggerWithLevel(&m, ctxd.NewAtomicLevel(ctxd.WarnLevel)),
+		ctxd.FlightRecorderConfig{MaxBytes: 1000},
+	)
+
+	ctx := ctxd.WithFlightRecorder(context.Background())
+
+	l.Info(ctx, "bytes", "v", make([]byte, 1000))
+	l.Info(ctx, "ints", "v", make([]int, 200))
+	l.Info(ctx, "map", "v", map[int]int{1: 1, 2: 2})
+	l.Info(ctx, "error", "v", errors.New(strings.Repeat("a", 1000)))
+	l.Info(ctx, "stringer", "v", time.Duration(1))
+
+	// Values are not formatted to estimate size.
+	fc := &formatCounter{}
+	l.Info(ctx, "counter", "v", fc)
+	assert.Equal(t, 0, fc.calls)
+
+	l.Error(ctx, "failed")
+
+	messages := make([]string, 0, len(m.LoggedEntries))
+	for _, e := range m.LoggedEntries {
+		messages = append(messages, e.Message)
+	}
+
+	assert.Equal(t, []string{"map", "error", "stringer", "counter", "failed"}, messages)
+}
+
+type formatCounter struct {
+	calls int
+}
+
+func (f *formatCounter) String() string {
+	f.calls++
+
+	return "counter"
+}
+
+func TestFlightRecorderConfig_Level(t *testing.T) {
+	m := ctxd.LoggerMock{}
+
+	// plainLogger does not implement LevelEnabler, so Level defines what is buffered.
+	l := ctxd.LoggerWithFlightRecorder(plainLogger{&m}, ctxd.FlightRecorderConfig{
+		Level: ctxd.NewAtomicLevel(ctxd.InfoLevel),
+	})
+
+	ctx := ctxd.WithFlightRecorder(context.Background())
+
+	assert.True(t, ctxd.Enabled(ctx, l, ctxd.DebugLevel))
+	assert.False(t, ctxd.Enabled(context.Background(), l, ctxd.DebugLevel))
+	assert.True(t, ctxd.Enabled(context.Background(), l, ctxd.InfoLevel))
+
+	l.Debug(ctx, "buffered")
+	l.Info(ctx, "passed")
+	l.Debug(context.Background(), "discarded")
+
+	assert.Equal(t, "info: passed null\n", m.String())
+
+	l.Error(ctx, "failed")
+
+	assert.Equal(t, []string{"passed", "buffered", "failed"}, []string{
+		m.LoggedEntries[0].Message, m.LoggedEntries[1].Message, m.LoggedEntries[2].Message,
+	})
+}
@@ -0,0 +1,281 @@
+package ctxd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// JSONLogger writes log entries as JSON lines.
+//
+// Entry envelope (timestamp, level and message) uses keys from FieldNames, fields from context
+// are merged with call-site keys and values, latter take precedence on duplicate keys.
+type JSONLogger struct {
+	// Output receives log entries, os.Stderr is used if nil.
+	// Writer from context (see WithLogWriter) takes precedence over Output.
+	Output io.Writer
+
+	// FieldNames customizes envelope keys, empty names are replaced with defaults.
+	FieldNames FieldNames
+
+	// DebugEnabled allows Debug messages, otherwise minimal level is InfoLevel.
+	// Level from context (see WithLevel, WithDebug) takes precedence.
+	DebugEnabled bool
+
+	// OnError is called on failure to write an entry, errors are ignored if nil.
+	OnError func(err error)
+
+	// TimeNow returns current time, time.Now is used if nil.
+	TimeNow func() time.Time
+
+	once  sync.Once
+	names FieldNames
+	mu    sync.Mutex
+}
+
+var _ Logger = &JSONLogger{}
+
+// Enabled checks if messages of level would be logged in context.
+func (l *JSONLogger) Enabled(ctx context.Context, level Level) bool {
+	return level == ImportantLevel || levelEnabled(ctx, level, l.minLevel())
+}
+
+// Debug logs a message if debug is enabled in logger or in context.
+func (l *JSONLogger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, DebugLevel, l.minLevel()) {
+		l.log(ctx, "debug", msg, keysAndValues)
+	}
+}
+
+// Info logs a message.
+func (l *JSONLogger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, InfoLevel, l.minLevel()) {
+		l.log(ctx, "info", msg, keysAndValues)
+	}
+}
+
+// Important logs a message with level "info".
+func (l *JSONLogger) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.log(ctx, "info", msg, keysAndValues)
+}
+
+// Warn logs a message.
+func (l *JSONLogger) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, WarnLevel, l.minLevel()) {
+		l.log(ctx, "warn", msg, keysAndValues)
+	}
+}
+
+// Error logs a message.
+func (l *JSONLogger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, ErrorLevel, l.minLevel()) {
+		l.log(ctx, "error", msg, keysAndValues)
+	}
+}
+
+func (l *JSONLogger) minLevel() Level {
+	if l.DebugEnabled {
+		return DebugLevel
+	}
+
+	return InfoLevel
+}
+
+func (l *JSONLogger) log(ctx context.Context, level, msg string, keysAndValues []interface{}) {
+	l.once.Do(func() {
+		l.names = l.FieldNames.WithDefaults()
+	})
+
+	fp := acquireFields(ctx, keysAndValues)
+	bp := acquireBuf()
+	b := *bp
+
+	b = append(b, '{')
+	b = appendJSONString(b, l.names.Timestamp)
+	b = append(b, ':', '"')
+	b = timeNow(l.TimeNow).UTC().AppendFormat(b, time.RFC3339Nano)
+	b = append(b, '"', ',')
+	b = appendJSONString(b, l.names.Level)
+	b = append(b, ':')
+	b = appendJSONString(b, level)
+	b = append(b, ',')
+	b = appendJSONString(b, l.names.Message)
+	b = append(b, ':')
+	b = appendJSONString(b, msg)
+
+	for _, f := range *fp {
+		b = append(b, ',')
+		b = appendJSONString(b, f.key)
+		b = append(b, ':')
+		b = appendJSONValue(b, f.value)
+	}
+
+	b = append(b, '}', '\n')
+
+	if err := writeEntry(ctx, &l.mu, l.Output, b); err != nil && l.OnError != nil {
+		l.OnError(err)
+	}
+
+	releaseFields(fp)
+
+	*bp = b
+	releaseBuf(bp)
+}
+
+// appendJSONValue appends JSON representation of a value to b.
+//
+// Values that fail to marshal are replaced with error message.
+func appendJSONValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, "null"...)
+	case string:
+		return appendJSONString(b, v)
+	case bool:
+		return strconv.AppendBool(b, v)
+	case int:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int8:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case uint:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint8:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(b, v, 10)
+	case float32:
+		return appendJSONFloat(b, float64(v), 32)
+	case float64:
+		return appendJSONFloat(b, v, 64)
+	case time.Duration:
+		return strconv.AppendInt(b, int64(v), 10)
+	case time.Time:
+		b = append(b, '"')
+		b = v.AppendFormat(b, time.RFC3339Nano)
+
+		return append(b, '"')
+	case json.Marshaler:
+		if isNilPointer(v) {
+			return append(b, "null"...)
+		}
+
+		j, err := v.MarshalJSON()
+		if err != nil {
+			return appendJSONString(b, err.Error())
+		}
+
+		return append(b, j...)
+	case error:
+		if isNilPointer(v) {
+			return append(b, "null"...)
+		}
+
+		return appendJSONString(b, v.Error())
+	case fmt.Stringer:
+		if isNilPointer(v) {
+			return append(b, "null"...)
+		}
+
+		return appendJSONString(b, v.String())
+	}
+
+	j, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(b, err.Error())
+	}
+
+	return append(b, j...)
+}
+
+func appendJSONFloat(b []byte, f float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(b, `"NaN"`...)
+	case math.IsInf(f, 1):
+		return append(b, `"+Inf"`...)
+	case math.IsInf(f, -1):
+		return append(b, `"-Inf"`...)
+	}
+
+	return strconv.AppendFloat(b, f, 'g', -1, bitSize)
+}
+
+const hex = "0123456789abcdef"
+
+// appendJSONString appends quoted and escaped string to b.
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+
+				continue
+			}
+
+			b = append(b, s[start:i]...)
+
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+
+			i++
+			start = i
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+
+			continue
+		}
+
+		// U+2028 and U+2029 are valid JSON, but break JavaScript parsers.
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+
+			continue
+		}
+
+		i += size
+	}
+
+	b = append(b, s[start:]...)
+
+	return append(b, '"')
+}
@@ -0,0 +1,251 @@
+package ctxd_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeJSONLines(t *testing.T, s string) []map[string]interface{} {
+	t.Helper()
+
+	var res []map[string]interface{}
+
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		var m map[string]interface{}
+
+		require.NoError(t, json.Unmarshal([]byte(line), &m), line)
+
+		res = append(res, m)
+	}
+
+	return res
+}
+
+func TestJSONLogger(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.JSONLogger{Output: &buf}
+
+	ctx := ctxd.AddFields(context.Background(), "foo", 1, "bar", "abc")
+
+	l.Debug(ctx, "debug", "baz", 3)
+	l.Info(ctx, "info", "baz", 3, "bar", "def")
+	l.Important(ctx, "important", "baz", 3)
+	l.Warn(ctx, "warn", "baz", 3)
+	l.Error(ctx, "error", "baz", 3)
+	l.Debug(ctxd.WithDebug(ctx), "debug with debug", "baz", 3)
+
+	assert.True(t, strings.HasPrefix(buf.String(), `{"@timestamp":"`), buf.String())
+	assert.Contains(t, buf.String(), `","log.level":"info","message":"info","foo":1,"bar":"def","baz":3}`+"\n")
+
+	entries := decodeJSONLines(t, buf.String())
+	require.Len(t, entries, 5)
+
+	for _, e := range entries {
+		ts, err := time.Parse(time.RFC3339Nano, e["@timestamp"].(string))
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), ts, time.Minute)
+
+		delete(e, "@timestamp")
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{"log.level": "info", "message": "info", "foo": 1.0, "bar": "def", "baz": 3.0},
+		{"log.level": "info", "message": "important", "foo": 1.0, "bar": "abc", "baz": 3.0},
+		{"log.level": "warn", "message": "warn", "foo": 1.0, "bar": "abc", "baz": 3.0},
+		{"log.level": "error", "message": "error", "foo": 1.0, "bar": "abc", "baz": 3.0},
+		{"log.level": "debug", "message": "debug with debug", "foo": 1.0, "bar": "abc", "baz": 3.0},
+	}, entries)
+}
+
+func TestJSONLogger_FieldNames(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.JSONLogger{
+		Output:       &buf,
+		DebugEnabled: true,
+		FieldNames: ctxd.FieldNames{
+			Timestamp: "time",
+			Message:   "msg",
+		},
+	}
+
+	l.Debug(context.Background(), "hello")
+
+	entries := decodeJSONLines(t, buf.String())
+	require.Len(t, entries, 1)
+	assert.Equal(t, "debug", entries[0]["log.level"])
+	assert.Equal(t, "hello", entries[0]["msg"])
+	assert.NotEmpty(t, entries[0]["time"])
+}
+
+func TestJSONLogger_LogWriter(t *testing.T) {
+	buf := bytes.Buffer{}
+	ctxBuf := bytes.Buffer{}
+	l := ctxd.JSONLogger{Output: &buf}
+
+	ctx := ctxd.WithLogWriter(context.Background(), &ctxBuf)
+
+	l.Info(ctx, "hello")
+
+	assert.Empty(t, buf.String())
+	assert.Contains(t, ctxBuf.String(), `"message":"hello"}`)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(_ []byte) (int, error) {
+	return 0, errors.New("failed")
+}
+
+func TestJSONLogger_OnError(t *testing.T) {
+	var e error
+
+	l := ctxd.JSONLogger{
+		Output: failingWriter{},
+		OnError: func(err error) {
+			e = err
+		},
+	}
+
+	l.Info(context.Background(), "hello")
+	assert.EqualError(t, e, "failed")
+}
+
+func TestJSONLogger_values(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.JSONLogger{Output: &buf}
+
+	l.Info(context.Background(), "values \"quoted\"\n\t\x01\u2028\xff",
+		"nil", nil,
+		"bool", true,
+		"int8", int8(-8),
+		"uint64", uint64(64),
+		"float", 1.5,
+		"nan", math.NaN(),
+		"inf", math.Inf(-1),
+		"dur", time.Second,
+		"time", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		"err", errors.New("failed"),
+		"structErr", ctxd.NewError(context.Background(), "failed", "k", "v"),
+		"deferredJSON", ctxd.DeferredJSON(func() interface{} { return []int{1, 2} }),
+		"deferredString", ctxd.DeferredString(func() interface{} { return []int{1, 2} }),
+		"slice", []string{"a"},
+		"func", func() {},
+		123, "malformed",
+	)
+
+	s := buf.String()
+	s = s[strings.Index(s, `"log.level"`):]
+
+	assert.Equal(t, `"log.level":"info","message":"values \"quoted\"\n\t\u0001\u2028\ufffd",`+
+		`"nil":null,"bool":true,"int8":-8,"uint64":64,"float":1.5,"nan":"NaN","inf":"-Inf",`+
+		`"dur":1000000000,"time":"2020-01-02T03:04:05Z","err":"failed","structErr":"failed",`+
+		`"deferredJSON":[1,2],"deferredString":"[1 2]","slice":["a"],"func":"json: unsupported type: func()",`+
+		`"malformedFields":[123,"malformed"]}`+"\n", s)
+}
+
+func TestJSONLogger_malformedFields(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.JSONLogger{Output: &buf}
+	ctx := ctxd.AddFields(context.Background(), "a", 1, 5)
+
+	l.Info(ctx, "hello", "b", 2, 7)
+
+	s := buf.String()
+	s = s[strings.Index(s, `"log.level"`):]
+
+	// Malformed fields of context are not lost.
+	assert.Equal(t, `"log.level":"info","message":"hello","a":1,"malformedFields":[5,7],"b":2}`+"\n", s)
+}
+
+func BenchmarkJSONLogger_Info(b *testing.B) {
+	l := ctxd.JSONLogger{Output: io.Discard}
+	ctx := ctxd.AddFields(context.Background(), "foo", 1, "bar", "abc")
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		l.Info(ctx, "hello", "baz", 3, "quux", true)
+	}
+}
+
+func TestJSONLogger_contextLevel(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.JSONLogger{Output: &buf, DebugEnabled: true}
+	ctx := ctxd.WithLevel(context.Background(), ctxd.WarnLevel)
+
+	l.Debug(ctx, "debug")
+	l.Info(ctx, "info")
+	l.Important(ctx, "important")
+	l.Warn(ctx, "warn")
+
+	entries := decodeJSONLines(t, buf.String())
+	require.Len(t, entries, 2)
+	assert.Equal(t, "important", entries[0]["message"])
+	assert.Equal(t, "warn", entries[1]["message"])
+}
+
+func TestJSONLogger_TimeNow(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.JSONLogger{
+		Output:  &buf,
+		TimeNow: fakeClock(),
+	}
+
+	l.Info(context.Background(), "first")
+	l.Info(context.Background(), "second")
+
+	assert.Equal(t, `{"@timestamp":"2020-01-02T03:04:06Z","log.level":"info","message":"first"}
+{"@timestamp":"2020-01-02T03:04:07Z","log.level":"info","message":"second"}
+`, buf.String())
+}
+
+type fieldErr struct {
+	msg string
+}
+
+func (e *fieldErr) Error() string {
+	return e.msg
+}
+
+type fieldStringer struct {
+	s string
+}
+
+func (s *fieldStringer) String() string {
+	return s.s
+}
+
+type fieldMarshaler struct {
+	j string
+}
+
+func (m *fieldMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(m.j), nil
+}
+
+func TestJSONLogger_nilPointers(t *testing.T) {
+	buf := bytes.Buffer{}
+	l := ctxd.JSONLogger{Output: &buf}
+
+	l.Info(context.Background(), "m",
+		"err", (*fieldErr)(nil),
+		"stringer", (*fieldStringer)(nil),
+		"marshaler", (*fieldMarshaler)(nil),
+		"ok", &fieldErr{msg: "failed"},
+	)
+
+	s := buf.String()
+	assert.Equal(t, `"message":"m","err":null,"stringer":null,"marshaler":null,"ok":"failed"}`+"\n",
+		s[strings.Index(s, `"message"`):])
+}
@@ -0,0 +1,265 @@
+package ctxd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Level defines severity of a log message.
+type Level int8
+
+// Levels of log messages, zero value is InfoLevel.
+const (
+	DebugLevel Level = iota - 1
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+
+	// ImportantLevel is a level of Important messages, such messages bypass level filtering.
+	ImportantLevel
+)
+
+// String returns level name.
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	case ImportantLevel:
+		return "important"
+	default:
+		return "Level(" + strconv.Itoa(int(l)) + ")"
+	}
+}
+
+// ErrUnknownLevel is returned when level name can not be parsed.
+const ErrUnknownLevel = SentinelError("unknown level")
+
+// ParseLevel parses case-insensitive level name, empty name is rejected with ErrUnknownLevel.
+func ParseLevel(s string) (Level, error) {
+	var l Level
+
+	err := l.UnmarshalText([]byte(s))
+
+	return l, err
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *Level) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "debug":
+		*l = DebugLevel
+	case "info":
+		*l = InfoLevel
+	case "warn", "warning":
+		*l = WarnLevel
+	case "error":
+		*l = ErrorLevel
+	case "important":
+		*l = ImportantLevel
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownLevel, text)
+	}
+
+	return nil
+}
+
+// Log sends a message to logger method that matches level.
+//
+// Levels below DebugLevel are logged with Debug, unknown levels above ImportantLevel are logged with Error.
+func Log(ctx context.Context, logger Logger, level Level, msg string, keysAndValues ...interface{}) {
+	switch {
+	case level <= DebugLevel:
+		logger.Debug(ctx, msg, keysAndValues...)
+	case level == InfoLevel:
+		logger.Info(ctx, msg, keysAndValues...)
+	case level == WarnLevel:
+		logger.Warn(ctx, msg, keysAndValues...)
+	case level == ImportantLevel:
+		logger.Important(ctx, msg, keysAndValues...)
+	default:
+		logger.Error(ctx, msg, keysAndValues...)
+	}
+}
+
+// LogErrorAt pushes error value to a contextualized logger with a method that matches level.
+//
+// It is equivalent to LogError with a logger method, but level can be chosen dynamically,
+// e.g. depending on error type.
+func LogErrorAt(ctx context.Context, err error, logger Logger, level Level) {
+	LogError(ctx, err, func(ctx context.Context, msg string, keysAndValues ...interface{}) {
+		Log(ctx, logger, level, msg, keysAndValues...)
+	})
+}
+
+type levelCtxKey struct{}
+
+// WithLevel returns context with level override.
+//
+// Level from context takes precedence over level of logger, so that messages of
+// particular context can be raised to debug or lowered, e.g. to warnings only.
+func WithLevel(ctx context.Context, level Level) context.Context {
+	return context.WithValue(ctx, levelCtxKey{}, level)
+}
+
+// LevelFrom returns level override found in context.
+func LevelFrom(ctx context.Context) (Level, bool) {
+	l, ok := ctx.Value(levelCtxKey{}).(Level)
+
+	return l, ok
+}
+
+// levelEnabled checks if level is allowed by context level override or by minimal level.
+func levelEnabled(ctx context.Context, level, minLevel Level) bool {
+	if l, ok := LevelFrom(ctx); ok {
+		return level >= l
+	}
+
+	return level >= minLevel
+}
+
+// AtomicLevel is a concurrency-safe level holder.
+//
+// Zero value holds InfoLevel.
+type AtomicLevel struct {
+	level int32
+}
+
+// NewAtomicLevel creates level holder with initial level.
+func NewAtomicLevel(level Level) *AtomicLevel {
+	a := AtomicLevel{}
+	a.SetLevel(level)
+
+	return &a
+}
+
+// Level returns current level.
+func (a *AtomicLevel) Level() Level {
+	return Level(atomic.LoadInt32(&a.level))
+}
+
+// SetLevel changes current level.
+func (a *AtomicLevel) SetLevel(level Level) {
+	atomic.StoreInt32(&a.level, int32(level))
+}
+
+// Enabled returns true if messages of level are allowed by current level.
+func (a *AtomicLevel) Enabled(level Level) bool {
+	return level >= a.Level()
+}
+
+type levelPayload struct {
+	Level *Level `json:"level"`
+}
+
+// ServeHTTP exposes current level as JSON.
+//
+// GET request returns current level, e.g. {"level":"info"}.
+// PUT request changes current level with JSON body, e.g. {"level":"debug"}, and returns new level.
+func (a *AtomicLevel) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var p levelPayload
+
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			writeLevelError(rw, http.StatusBadRequest, "failed to decode request: "+err.Error())
+
+			return
+		}
+
+		if p.Level == nil {
+			writeLevelError(rw, http.StatusBadRequest, "missing level")
+
+			return
+		}
+
+		a.SetLevel(*p.Level)
+	default:
+		rw.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+		writeLevelError(rw, http.StatusMethodNotAllowed, "only GET and PUT are supported")
+
+		return
+	}
+
+	l := a.Level()
+
+	_ = json.NewEncoder(rw).Encode(levelPayload{Level: &l})
+}
+
+func writeLevelError(rw http.ResponseWriter, status int, msg string) {
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(struct {
+		Error string `json:"error"`
+	}{Error: msg})
+}
+
+// LoggerWithLevel instruments contextualized logger with level filter.
+//
+// Messages below current level of holder are discarded, Important messages are never discarded.
+// Level from context (see WithLevel, WithDebug) takes precedence over level of holder.
+func LoggerWithLevel(logger Logger, level *AtomicLevel) Logger {
+	return &withLevel{
+		logger: logger,
+		level:  level,
+	}
+}
+
+type withLevel struct {
+	logger Logger
+	level  *AtomicLevel
+}
+
+func (w *withLevel) Enabled(ctx context.Context, level Level) bool {
+	if level != ImportantLevel && !levelEnabled(ctx, level, w.level.Level()) {
+		return false
+	}
+
+	return Enabled(ctx, w.logger, level)
+}
+
+func (w *withLevel) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, DebugLevel, w.level.Level()) {
+		w.logger.Debug(ctx, msg, keysAndValues...)
+	}
+}
+
+func (w *withLevel) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, InfoLevel, w.level.Level()) {
+		w.logger.Info(ctx, msg, keysAndValues...)
+	}
+}
+
+func (w *withLevel) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Important(ctx, msg, keysAndValues...)
+}
+
+func (w *withLevel) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, WarnLevel, w.level.Level()) {
+		w.logger.Warn(ctx, msg, keysAndValues...)
+	}
+}
+
+func (w *withLevel) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if levelEnabled(ctx, ErrorLevel, w.level.Level()) {
+		w.logger.Error(ctx, msg, keysAndValues...)
+	}
+}
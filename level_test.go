@@ -0,0 +1,249 @@
+package ctxd_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevel_String(t *testing.T) {
+	assert.Equal(t, "debug", ctxd.DebugLevel.String())
+	assert.Equal(t, "info", ctxd.InfoLevel.String())
+	assert.Equal(t, "warn", ctxd.WarnLevel.String())
+	assert.Equal(t, "error", ctxd.ErrorLevel.String())
+	assert.Equal(t, "important", ctxd.ImportantLevel.String())
+	assert.Equal(t, "Level(10)", ctxd.Level(10).String())
+}
+
+func TestLoggerWithLevel(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	lvl := ctxd.AtomicLevel{}
+	l := ctxd.LoggerWithLevel(&lm, &lvl)
+	ctx := context.Background()
+
+	logAll := func(prefix string) {
+		l.Debug(ctx, prefix+" debug")
+		l.Info(ctx, prefix+" info")
+		l.Important(ctx, prefix+" important")
+		l.Warn(ctx, prefix+" warn")
+		l.Error(ctx, prefix+" error")
+		l.Debug(ctxd.WithDebug(ctx), prefix+" debug in context")
+	}
+
+	assert.Equal(t, ctxd.InfoLevel, lvl.Level())
+	logAll("default")
+
+	lvl.SetLevel(ctxd.ErrorLevel)
+	logAll("error")
+
+	lvl.SetLevel(ctxd.DebugLevel)
+	l.Debug(ctx, "debug")
+
+	assert.Equal(t, `info: default info null
+important: default important null
+warn: default warn null
+error: default error null
+debug mode, debug: default debug in context null
+important: error important null
+error: error error null
+debug mode, debug: error debug in context null
+debug: debug null
+`, lm.String())
+}
+
+func TestAtomicLevel_concurrent(t *testing.T) {
+	lvl := ctxd.NewAtomicLevel(ctxd.WarnLevel)
+	l := ctxd.LoggerWithLevel(ctxd.NoOpLogger{}, lvl)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			lvl.SetLevel(ctxd.Level(i%4 - 1))
+			l.Info(context.Background(), "hello")
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, l := range map[string]ctxd.Level{
+		"debug":   ctxd.DebugLevel,
+		"INFO":    ctxd.InfoLevel,
+		"Warn":    ctxd.WarnLevel,
+		"warning": ctxd.WarnLevel,
+		"error":   ctxd.ErrorLevel,
+
+		"important": ctxd.ImportantLevel,
+	} {
+		lvl, err := ctxd.ParseLevel(s)
+		require.NoError(t, err)
+		assert.Equal(t, l, lvl, s)
+	}
+
+	_, err := ctxd.ParseLevel("fatal")
+	assert.True(t, errors.Is(err, ctxd.ErrUnknownLevel))
+	assert.EqualError(t, err, `unknown level: "fatal"`)
+
+	_, err = ctxd.ParseLevel("")
+	assert.True(t, errors.Is(err, ctxd.ErrUnknownLevel))
+	assert.EqualError(t, err, `unknown level: ""`)
+}
+
+func TestAtomicLevel_ServeHTTP(t *testing.T) {
+	lvl := ctxd.NewAtomicLevel(ctxd.WarnLevel)
+	srv := httptest.NewServer(lvl)
+
+	defer srv.Close()
+
+	do := func(method, body string) (int, string) {
+		t.Helper()
+
+		req, err := http.NewRequest(method, srv.URL, strings.NewReader(body))
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, resp.Body.Close())
+		}()
+
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, string(b)
+	}
+
+	status, body := do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"level":"warn"}`+"\n", body)
+
+	status, body = do(http.MethodPut, `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, `{"level":"debug"}`+"\n", body)
+	assert.Equal(t, ctxd.DebugLevel, lvl.Level())
+
+	status, body = do(http.MethodPut, `{"level":"fatal"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, `{"error":"failed to decode request: unknown level: \"fatal\""}`+"\n", body)
+
+	status, body = do(http.MethodPut, `{"level":""}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, `{"error":"failed to decode request: unknown level: \"\""}`+"\n", body)
+	assert.Equal(t, ctxd.DebugLevel, lvl.Level())
+
+	status, body = do(http.MethodPut, `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, `{"error":"missing level"}`+"\n", body)
+
+	status, body = do(http.MethodPost, `{"level":"info"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+	assert.Equal(t, `{"error":"only GET and PUT are supported"}`+"\n", body)
+	assert.Equal(t, ctxd.DebugLevel, lvl.Level())
+}
+
+func TestWithLevel(t *testing.T) {
+	ctx := context.Background()
+
+	_, ok := ctxd.LevelFrom(ctx)
+	assert.False(t, ok)
+	assert.False(t, ctxd.IsDebug(ctx))
+
+	ctx = ctxd.WithDebug(ctx)
+	lvl, ok := ctxd.LevelFrom(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, ctxd.DebugLevel, lvl)
+	assert.True(t, ctxd.IsDebug(ctx))
+
+	ctx = ctxd.WithLevel(ctx, ctxd.WarnLevel)
+	lvl, ok = ctxd.LevelFrom(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, ctxd.WarnLevel, lvl)
+	assert.False(t, ctxd.IsDebug(ctx))
+}
+
+func TestLoggerWithLevel_contextLevel(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithLevel(&lm, ctxd.NewAtomicLevel(ctxd.InfoLevel))
+	ctx := ctxd.WithLevel(context.Background(), ctxd.WarnLevel)
+
+	l.Debug(ctx, "debug")
+	l.Info(ctx, "info")
+	l.Important(ctx, "important")
+	l.Warn(ctx, "warn")
+	l.Error(ctx, "error")
+
+	ctx = ctxd.WithLevel(ctx, ctxd.DebugLevel)
+	l.Debug(ctx, "debug raised")
+
+	assert.Equal(t, `important: important null
+warn: warn null
+error: error null
+debug mode, debug: debug raised null
+`, lm.String())
+}
+
+func TestLevel_JSON(t *testing.T) {
+	j, err := json.Marshal(map[string]ctxd.Level{"a": ctxd.WarnLevel, "b": ctxd.ImportantLevel})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"warn","b":"important"}`, string(j))
+
+	var v struct {
+		Level ctxd.Level `json:"level"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"level":"DEBUG"}`), &v))
+	assert.Equal(t, ctxd.DebugLevel, v.Level)
+
+	assert.EqualError(t, json.Unmarshal([]byte(`{"level":"fatal"}`), &v), `unknown level: "fatal"`)
+}
+
+func TestLog_level(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	ctx := context.Background()
+
+	for _, lvl := range []ctxd.Level{
+		ctxd.Level(-5), ctxd.DebugLevel, ctxd.InfoLevel, ctxd.ImportantLevel, ctxd.WarnLevel, ctxd.ErrorLevel, ctxd.Level(10),
+	} {
+		ctxd.Log(ctx, &lm, lvl, lvl.String(), "a", 1)
+	}
+
+	assert.Equal(t, `debug: Level(-5) {"a":1}
+debug: debug {"a":1}
+info: info {"a":1}
+important: important {"a":1}
+warn: warn {"a":1}
+error: error {"a":1}
+error: Level(10) {"a":1}
+`, lm.String())
+}
+
+func TestLogErrorAt(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	ctx := ctxd.AddFields(context.Background(), "foo", 1)
+
+	ctxd.LogErrorAt(ctx, ctxd.NewError(ctx, "failed", "bar", 2), &lm, ctxd.WarnLevel)
+	ctxd.LogErrorAt(ctx, errors.New("plain"), &lm, ctxd.InfoLevel)
+	ctxd.LogErrorAt(ctx, nil, &lm, ctxd.ErrorLevel)
+
+	assert.Equal(t, `warn: failed {"bar":2,"foo":1}
+info: plain {"foo":1}
+`, lm.String())
+}
@@ -3,7 +3,9 @@ package ctxd
 import (
 	"context"
 	"io"
+	"os"
 	"sync"
+	"time"
 )
 
 // Logger is a contextualized structured logger.
@@ -28,15 +30,39 @@ type Logger interface {
 	Error(ctx context.Context, msg string, keysAndValues ...interface{})
 }
 
+// timeNow returns result of f or time.Now if f is nil.
+func timeNow(f func() time.Time) time.Time {
+	if f != nil {
+		return f()
+	}
+
+	return time.Now()
+}
+
+// LevelEnabler is an optional interface of Logger to check if messages of level would be logged in context.
+//
+// It can be used to skip expensive computation of keys and values.
+type LevelEnabler interface {
+	Enabled(ctx context.Context, level Level) bool
+}
+
+// Enabled checks if logger would log messages of level in context.
+//
+// Logger that does not implement LevelEnabler is considered enabled for all levels.
+func Enabled(ctx context.Context, logger Logger, level Level) bool {
+	if le, ok := logger.(LevelEnabler); ok {
+		return le.Enabled(ctx, level)
+	}
+
+	return true
+}
+
 // LoggerProvider is an embeddable provider interface.
 type LoggerProvider interface {
 	CtxdLogger() Logger
 }
 
-type (
-	isDebugCtxKey   struct{}
-	logWriterCtxKey struct{}
-)
+type logWriterCtxKey struct{}
 
 type syncWriter struct {
 	m sync.Mutex
@@ -66,16 +92,40 @@ func LogWriter(ctx context.Context) io.Writer {
 	return w
 }
 
+// writeEntry writes an entry to a writer from context or to output guarded by mutex, os.Stderr is used if output is nil.
+func writeEntry(ctx context.Context, mu *sync.Mutex, output io.Writer, entry []byte) error {
+	if w := LogWriter(ctx); w != nil {
+		_, err := w.Write(entry)
+
+		return err
+	}
+
+	if output == nil {
+		output = os.Stderr
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	_, err := output.Write(entry)
+
+	return err
+}
+
 // WithDebug returns context with debug flag enabled.
+//
+// It is a shortcut for WithLevel(ctx, DebugLevel).
 func WithDebug(ctx context.Context) context.Context {
-	return context.WithValue(ctx, isDebugCtxKey{}, true)
+	return WithLevel(ctx, DebugLevel)
 }
 
 // IsDebug returns true if debug flag is enabled in context.
+//
+// Debug flag is enabled if context level (see WithLevel) allows DebugLevel.
 func IsDebug(ctx context.Context) bool {
-	_, ok := ctx.Value(isDebugCtxKey{}).(bool)
+	l, ok := LevelFrom(ctx)
 
-	return ok
+	return ok && l <= DebugLevel
 }
 
 // LoggerWithFields instruments contextualized logger with global fields.
@@ -91,6 +141,10 @@ type withFields struct {
 	keysAndValues []interface{}
 }
 
+func (w *withFields) Enabled(ctx context.Context, level Level) bool {
+	return Enabled(ctx, w.logger, level)
+}
+
 func (w *withFields) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
 	w.logger.Debug(AddFields(ctx, w.keysAndValues...), msg, keysAndValues...)
 }
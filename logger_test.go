@@ -28,3 +28,41 @@ error: error {"key1":1,"key2":"abc","key3":3,"key4":4}
 debug mode, info: info with debug {"key1":1,"key2":"abc","key3":3,"key4":4}
 `, l.String())
 }
+
+// plainLogger hides optional interfaces of embedded logger.
+type plainLogger struct {
+	ctxd.Logger
+}
+
+func TestEnabled(t *testing.T) {
+	ctx := context.Background()
+
+	// Logger without LevelEnabler is enabled for all levels.
+	assert.True(t, ctxd.Enabled(ctx, plainLogger{Logger: ctxd.NoOpLogger{}}, ctxd.DebugLevel))
+	assert.False(t, ctxd.Enabled(ctx, ctxd.NoOpLogger{}, ctxd.ImportantLevel))
+	assert.False(t, ctxd.Enabled(ctx, ctxd.LoggerWithFields(ctxd.NoOpLogger{}, "k", "v"), ctxd.ErrorLevel))
+
+	j := &ctxd.JSONLogger{}
+	assert.False(t, ctxd.Enabled(ctx, j, ctxd.DebugLevel))
+	assert.True(t, ctxd.Enabled(ctx, j, ctxd.InfoLevel))
+	assert.True(t, ctxd.Enabled(ctxd.WithDebug(ctx), j, ctxd.DebugLevel))
+	assert.True(t, ctxd.Enabled(ctxd.WithLevel(ctx, ctxd.ErrorLevel), j, ctxd.ImportantLevel))
+	assert.False(t, ctxd.Enabled(ctxd.WithLevel(ctx, ctxd.ErrorLevel), j, ctxd.WarnLevel))
+
+	w := ctxd.LoggerWithLevel(ctxd.LoggerWithFields(&ctxd.LoggerMock{}, "k", "v"), ctxd.NewAtomicLevel(ctxd.WarnLevel))
+	assert.False(t, ctxd.Enabled(ctx, w, ctxd.InfoLevel))
+	assert.True(t, ctxd.Enabled(ctx, w, ctxd.WarnLevel))
+	assert.True(t, ctxd.Enabled(ctx, w, ctxd.ImportantLevel))
+
+	w = ctxd.LoggerWithLevel(ctxd.NoOpLogger{}, ctxd.NewAtomicLevel(ctxd.DebugLevel))
+	assert.False(t, ctxd.Enabled(ctx, w, ctxd.ErrorLevel))
+
+	c := ctxd.ChainLogger(ctxd.NoOpLogger{}, func(next ctxd.Handler) ctxd.Handler { return next })
+	assert.False(t, ctxd.Enabled(ctx, c, ctxd.DebugLevel))
+	assert.True(t, ctxd.Enabled(ctx, ctxd.ChainLogger(j), ctxd.InfoLevel))
+	assert.False(t, ctxd.Enabled(ctx, ctxd.ChainLogger(j), ctxd.DebugLevel))
+
+	m := ctxd.MultiLogger(ctxd.NoOpLogger{}, j)
+	assert.False(t, ctxd.Enabled(ctx, m, ctxd.DebugLevel))
+	assert.True(t, ctxd.Enabled(ctx, m, ctxd.InfoLevel))
+}
@@ -0,0 +1,92 @@
+package ctxd
+
+import "context"
+
+// Entry is a log entry that is passed through a chain of handlers.
+type Entry struct {
+	Ctx           context.Context //nolint:containedctx // Entry carries context of a logger call.
+	Level         Level
+	Message       string
+	KeysAndValues []interface{}
+}
+
+// Handler processes log entries.
+type Handler interface {
+	Handle(e Entry)
+}
+
+// HandlerFunc is a function adapter for Handler.
+type HandlerFunc func(e Entry)
+
+// Handle calls f(e).
+func (f HandlerFunc) Handle(e Entry) {
+	f(e)
+}
+
+// Middleware is a decorator of Handler.
+//
+// Middleware can alter the entry (e.g. add fields or redact values), drop it by not calling next handler,
+// or route it to other loggers.
+type Middleware func(next Handler) Handler
+
+// LoggerHandler returns a Handler that sends entries to a method of logger that matches entry level (see Log).
+func LoggerHandler(logger Logger) Handler {
+	return HandlerFunc(func(e Entry) {
+		Log(e.Ctx, logger, e.Level, e.Message, e.KeysAndValues...)
+	})
+}
+
+// HandlerLogger returns a Logger that sends entries to a handler.
+func HandlerLogger(h Handler) Logger {
+	return handlerLogger{h: h}
+}
+
+// ChainLogger instruments logger with middlewares.
+//
+// First middleware is the outermost, it receives entries before the others.
+// Enabled check (see LevelEnabler) is delegated to logger.
+func ChainLogger(logger Logger, middlewares ...Middleware) Logger {
+	h := LoggerHandler(logger)
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+
+	return chainLogger{
+		handlerLogger: handlerLogger{h: h},
+		logger:        logger,
+	}
+}
+
+type chainLogger struct {
+	handlerLogger
+	logger Logger
+}
+
+func (l chainLogger) Enabled(ctx context.Context, level Level) bool {
+	return Enabled(ctx, l.logger, level)
+}
+
+type handlerLogger struct {
+	h Handler
+}
+
+func (l handlerLogger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.h.Handle(Entry{Ctx: ctx, Level: DebugLevel, Message: msg, KeysAndValues: keysAndValues})
+}
+
+func (l handlerLogger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.h.Handle(Entry{Ctx: ctx, Level: InfoLevel, Message: msg, KeysAndValues: keysAndValues})
+}
+
+func (l handlerLogger) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.h.Handle(Entry{Ctx: ctx, Level: ImportantLevel, Message: msg, KeysAndValues: keysAndValues})
+}
+
+func (l handlerLogger) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.h.Handle(Entry{Ctx: ctx, Level: WarnLevel, Message: msg, KeysAndValues: keysAndValues})
+}
+
+func (l handlerLogger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	l.h.Handle(Entry{Ctx: ctx, Level: ErrorLevel, Message: msg, KeysAndValues: keysAndValues})
+}
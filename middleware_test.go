@@ -0,0 +1,61 @@
+package ctxd_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainLogger(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+
+	var order []string
+
+	trace := func(name string) ctxd.Middleware {
+		return func(next ctxd.Handler) ctxd.Handler {
+			return ctxd.HandlerFunc(func(e ctxd.Entry) {
+				order = append(order, name)
+				next.Handle(e)
+			})
+		}
+	}
+
+	enrich := func(next ctxd.Handler) ctxd.Handler {
+		return ctxd.HandlerFunc(func(e ctxd.Entry) {
+			e.KeysAndValues = append(e.KeysAndValues, "level", e.Level.String())
+			next.Handle(e)
+		})
+	}
+
+	dropHealth := func(next ctxd.Handler) ctxd.Handler {
+		return ctxd.HandlerFunc(func(e ctxd.Entry) {
+			if !strings.HasPrefix(e.Message, "health") {
+				next.Handle(e)
+			}
+		})
+	}
+
+	l := ctxd.ChainLogger(&lm, trace("first"), dropHealth, enrich, trace("last"))
+	ctx := ctxd.AddFields(context.Background(), "foo", 1)
+
+	l.Debug(ctx, "debug")
+	l.Info(ctx, "info")
+	l.Info(ctx, "health check")
+	l.Important(ctx, "important")
+	l.Warn(ctx, "warn")
+	l.Error(ctx, "error")
+
+	assert.Equal(t, `debug: debug {"foo":1,"level":"debug"}
+info: info {"foo":1,"level":"info"}
+important: important {"foo":1,"level":"important"}
+warn: warn {"foo":1,"level":"warn"}
+error: error {"foo":1,"level":"error"}
+`, lm.String())
+
+	assert.Equal(t, []string{
+		"first", "last", "first", "last", "first", "first", "last", "first", "last", "first", "last",
+	}, order)
+}
@@ -4,22 +4,39 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 )
 
 // LoggerMock logs messages to internal buffer.
+//
+// All messages are logged unless level is lowered in context with WithLevel.
 type LoggerMock struct {
 	OnError func(err error)
 
+	// TimeNow returns time of logged entries, time.Now is used if nil.
+	TimeNow func() time.Time
+
+	// TimeFormat enables timestamp in text output with a layout, e.g. time.RFC3339.
+	TimeFormat string
+
 	sync.Mutex
 	bytes.Buffer
-	LoggedEntries []struct {
-		Time    time.Time              `json:"time"`
-		Level   string                 `json:"level"`
-		Message string                 `json:"message"`
-		Data    map[string]interface{} `json:"data,omitempty"`
-	}
+
+	// LoggedEntries should be accessed with embedded Mutex locked, Entries returns a concurrency-safe snapshot.
+	LoggedEntries LoggedEntries
+}
+
+// LoggedEntry is an entry logged with LoggerMock.
+type LoggedEntry struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Data    map[string]interface{} `json:"data,omitempty"`
 }
 
 func (m *LoggerMock) failed(err error) bool {
@@ -49,18 +66,22 @@ func (m *LoggerMock) log(ctx context.Context, level, msg string, keysAndValues [
 		return
 	}
 
-	m.LoggedEntries = append(m.LoggedEntries, struct {
-		Time    time.Time              `json:"time"`
-		Level   string                 `json:"level"`
-		Message string                 `json:"message"`
-		Data    map[string]interface{} `json:"data,omitempty"`
-	}{Time: time.Now(), Level: level, Message: msg, Data: data})
+	now := timeNow(m.TimeNow)
+
+	m.LoggedEntries = append(m.LoggedEntries, LoggedEntry{Time: now, Level: level, Message: msg, Data: data})
 
 	out := LogWriter(ctx)
 	if out == nil {
 		out = m
 	}
 
+	if m.TimeFormat != "" {
+		_, err = out.Write([]byte(now.Format(m.TimeFormat) + " "))
+		if m.failed(err) {
+			return
+		}
+	}
+
 	if IsDebug(ctx) {
 		_, err = out.Write([]byte("debug mode, "))
 		if m.failed(err) {
@@ -84,14 +105,23 @@ func (m *LoggerMock) log(ctx context.Context, level, msg string, keysAndValues [
 	}
 }
 
+// Enabled checks if messages of level would be logged in context.
+func (m *LoggerMock) Enabled(ctx context.Context, level Level) bool {
+	return level == ImportantLevel || levelEnabled(ctx, level, DebugLevel)
+}
+
 // Debug logs a message.
 func (m *LoggerMock) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	m.log(ctx, "debug", msg, keysAndValues)
+	if levelEnabled(ctx, DebugLevel, DebugLevel) {
+		m.log(ctx, "debug", msg, keysAndValues)
+	}
 }
 
 // Info logs a message.
 func (m *LoggerMock) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	m.log(ctx, "info", msg, keysAndValues)
+	if levelEnabled(ctx, InfoLevel, DebugLevel) {
+		m.log(ctx, "info", msg, keysAndValues)
+	}
 }
 
 // Important logs a message.
@@ -101,10 +131,172 @@ func (m *LoggerMock) Important(ctx context.Context, msg string, keysAndValues ..
 
 // Warn logs a message.
 func (m *LoggerMock) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	m.log(ctx, "warn", msg, keysAndValues)
+	if levelEnabled(ctx, WarnLevel, DebugLevel) {
+		m.log(ctx, "warn", msg, keysAndValues)
+	}
 }
 
 // Error logs a message.
 func (m *LoggerMock) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	m.log(ctx, "error", msg, keysAndValues)
+	if levelEnabled(ctx, ErrorLevel, DebugLevel) {
+		m.log(ctx, "error", msg, keysAndValues)
+	}
+}
+
+// Entries returns a snapshot of logged entries.
+func (m *LoggerMock) Entries() LoggedEntries {
+	m.Lock()
+	defer m.Unlock()
+
+	return append(LoggedEntries(nil), m.LoggedEntries...)
+}
+
+// Reset removes logged entries and clears buffer.
+func (m *LoggerMock) Reset() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.LoggedEntries = nil
+	m.Buffer.Reset()
+}
+
+// TestingT is an interface of *testing.T used by assertions, it is compatible with testify.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// AssertLogged checks that an entry with level, message and fields was logged and reports failure to t otherwise.
+//
+// Fields are matched as a subset of entry data, nil fields match any data.
+func (m *LoggerMock) AssertLogged(t TestingT, level Level, msg string, fields map[string]interface{}) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	entries := m.Entries()
+	if len(entries.filter(level, msg, fields)) > 0 {
+		return true
+	}
+
+	t.Errorf("entry is not logged: %s: %s %s\nlogged entries:\n%s", level, msg, marshalFields(fields), entries)
+
+	return false
+}
+
+// AssertNotLogged checks that no entry with level, message and fields was logged and reports failure to t otherwise.
+//
+// Fields are matched as a subset of entry data, nil fields match any data.
+func (m *LoggerMock) AssertNotLogged(t TestingT, level Level, msg string, fields map[string]interface{}) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	found := m.Entries().filter(level, msg, fields)
+	if len(found) == 0 {
+		return true
+	}
+
+	t.Errorf("unexpected entry is logged: %s: %s %s\nmatching entries:\n%s", level, msg, marshalFields(fields), found)
+
+	return false
+}
+
+// LoggedEntries is a list of entries logged with LoggerMock.
+type LoggedEntries []LoggedEntry
+
+// String returns entries as lines of "level: message {data}".
+func (le LoggedEntries) String() string {
+	b := strings.Builder{}
+
+	for _, e := range le {
+		b.WriteString(e.Level + ": " + e.Message + " " + marshalFields(e.Data) + "\n")
+	}
+
+	return b.String()
+}
+
+// Filter returns entries that satisfy f.
+func (le LoggedEntries) Filter(f func(e LoggedEntry) bool) LoggedEntries {
+	var res LoggedEntries
+
+	for _, e := range le {
+		if f(e) {
+			res = append(res, e)
+		}
+	}
+
+	return res
+}
+
+// WithLevel returns entries of level.
+func (le LoggedEntries) WithLevel(level Level) LoggedEntries {
+	l := level.String()
+
+	return le.Filter(func(e LoggedEntry) bool {
+		return e.Level == l
+	})
+}
+
+// WithMessage returns entries with message containing substring.
+func (le LoggedEntries) WithMessage(substr string) LoggedEntries {
+	return le.Filter(func(e LoggedEntry) bool {
+		return strings.Contains(e.Message, substr)
+	})
+}
+
+// WithMessageRegexp returns entries with message matching regular expression.
+func (le LoggedEntries) WithMessageRegexp(re *regexp.Regexp) LoggedEntries {
+	return le.Filter(func(e LoggedEntry) bool {
+		return re.MatchString(e.Message)
+	})
+}
+
+// WithField returns entries having field key with value.
+func (le LoggedEntries) WithField(key string, value interface{}) LoggedEntries {
+	return le.Filter(func(e LoggedEntry) bool {
+		v, ok := e.Data[key]
+
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+// WithFieldKey returns entries having field key with any value.
+func (le LoggedEntries) WithFieldKey(key string) LoggedEntries {
+	return le.Filter(func(e LoggedEntry) bool {
+		_, ok := e.Data[key]
+
+		return ok
+	})
+}
+
+func (le LoggedEntries) filter(level Level, msg string, fields map[string]interface{}) LoggedEntries {
+	l := level.String()
+
+	return le.Filter(func(e LoggedEntry) bool {
+		return e.matches(l, msg, fields)
+	})
+}
+
+// matches checks if entry has level, message and fields as a subset of data.
+func (e LoggedEntry) matches(level, msg string, fields map[string]interface{}) bool {
+	if e.Level != level || e.Message != msg {
+		return false
+	}
+
+	for k, v := range fields {
+		if dv, ok := e.Data[k]; !ok || !reflect.DeepEqual(dv, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func marshalFields(fields map[string]interface{}) string {
+	j, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Sprintf("%v", fields)
+	}
+
+	return string(j)
 }
@@ -2,10 +2,15 @@ package ctxd_test
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bool64/ctxd"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoggerMock_Error(t *testing.T) {
@@ -57,3 +62,125 @@ important: important message {"bar":2,"baz":3,"foo":1}`+"\n", m.String(), m.Stri
 	assert.Equal(t, "important message", m.LoggedEntries[4].Message)
 	assert.Equal(t, data, m.LoggedEntries[4].Data)
 }
+
+func TestLoggerMock_contextLevel(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	ctx := ctxd.WithLevel(context.Background(), ctxd.ErrorLevel)
+
+	m.Debug(ctx, "debug")
+	m.Info(ctx, "info")
+	m.Important(ctx, "important")
+	m.Warn(ctx, "warn")
+	m.Error(ctx, "error")
+
+	assert.Equal(t, `important: important null
+error: error null
+`, m.String())
+}
+
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestLoggerMock_Entries(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	ctx := ctxd.AddFields(context.Background(), "foo", 1)
+
+	m.Debug(ctx, "debug message", "bar", "a")
+	m.Info(ctx, "info message", "bar", "b")
+	m.Error(ctx, "failed to connect", "bar", "c")
+	m.Error(context.Background(), "failed to read")
+
+	entries := m.Entries()
+	require.Len(t, entries, 4)
+
+	assert.Len(t, entries.WithLevel(ctxd.ErrorLevel), 2)
+	assert.Len(t, entries.WithMessage("message"), 2)
+	assert.Len(t, entries.WithMessageRegexp(regexp.MustCompile(`^failed to (connect|write)$`)), 1)
+	assert.Len(t, entries.WithField("foo", 1), 3)
+	assert.Len(t, entries.WithField("foo", "1"), 0)
+	assert.Len(t, entries.WithFieldKey("bar").WithLevel(ctxd.ErrorLevel), 1)
+	assert.Equal(t, `error: failed to connect {"bar":"c","foo":1}
+`, entries.WithField("bar", "c").String())
+
+	m.Reset()
+
+	assert.Empty(t, m.Entries())
+	assert.Empty(t, m.String())
+	assert.Len(t, entries, 4)
+}
+
+func TestLoggerMock_AssertLogged(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	ctx := ctxd.AddFields(context.Background(), "foo", 1)
+
+	m.Info(ctx, "hello", "bar", "a")
+
+	assert.True(t, m.AssertLogged(t, ctxd.InfoLevel, "hello", nil))
+	assert.True(t, m.AssertLogged(t, ctxd.InfoLevel, "hello", map[string]interface{}{"bar": "a"}))
+	assert.True(t, m.AssertNotLogged(t, ctxd.WarnLevel, "hello", nil))
+	assert.True(t, m.AssertNotLogged(t, ctxd.InfoLevel, "hello", map[string]interface{}{"bar": "b"}))
+
+	rt := &recordingT{}
+
+	assert.False(t, m.AssertLogged(rt, ctxd.InfoLevel, "hello", map[string]interface{}{"foo": 2}))
+	assert.False(t, m.AssertNotLogged(rt, ctxd.InfoLevel, "hello", map[string]interface{}{"foo": 1}))
+	assert.Equal(t, []string{
+		"entry is not logged: info: hello {\"foo\":2}\nlogged entries:\ninfo: hello {\"bar\":\"a\",\"foo\":1}\n",
+		"unexpected entry is logged: info: hello {\"foo\":1}\nmatching entries:\ninfo: hello {\"bar\":\"a\",\"foo\":1}\n",
+	}, rt.errors)
+}
+
+func TestLoggerMock_Entries_concurrent(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			m.Info(context.Background(), "hello")
+			m.Entries()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(t, m.Entries(), 10)
+}
+
+// fakeClock returns time that advances by a second on every call.
+func fakeClock() func() time.Time {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	return func() time.Time {
+		ts = ts.Add(time.Second)
+
+		return ts
+	}
+}
+
+func TestLoggerMock_TimeNow(t *testing.T) {
+	m := ctxd.LoggerMock{
+		TimeNow:    fakeClock(),
+		TimeFormat: time.RFC3339,
+	}
+
+	m.Info(context.Background(), "first")
+	m.Warn(ctxd.WithDebug(context.Background()), "second", "k", 1)
+
+	assert.Equal(t, `2020-01-02T03:04:06Z info: first null
+2020-01-02T03:04:07Z debug mode, warn: second {"k":1}
+`, m.String())
+
+	entries := m.Entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 6, 0, time.UTC), entries[0].Time)
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 7, 0, time.UTC), entries[1].Time)
+}
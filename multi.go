@@ -0,0 +1,84 @@
+package ctxd
+
+import (
+	"context"
+	"fmt"
+)
+
+// MultiLogger creates a logger that dispatches each call to all loggers.
+//
+// Branches can be filtered with LoggerWithLevel.
+// Panic in one branch does not prevent other branches from receiving the entry,
+// recovered panic is reported to other branches as an error.
+func MultiLogger(loggers ...Logger) Logger {
+	return multiLogger(loggers)
+}
+
+// MultiLoggerPanicMessage is a message of error entry that reports panic in a branch of MultiLogger.
+const MultiLoggerPanicMessage = "logger panicked"
+
+type multiLogger []Logger
+
+func (m multiLogger) dispatch(ctx context.Context, msg string, keysAndValues []interface{}, f func(l Logger) LogFunc) {
+	var (
+		panicked  []int
+		recovered []interface{}
+	)
+
+	for i, l := range m {
+		if r := safeLog(ctx, f(l), msg, keysAndValues); r != nil {
+			panicked = append(panicked, i)
+			recovered = append(recovered, r)
+		}
+	}
+
+	for j, i := range panicked {
+		for k, l := range m {
+			if k != i {
+				safeLog(ctx, l.Error, MultiLoggerPanicMessage,
+					[]interface{}{"panic", fmt.Sprintf("%v", recovered[j]), "logger.index", i, "log.message", msg})
+			}
+		}
+	}
+}
+
+// safeLog calls log function and returns recovered panic value.
+func safeLog(ctx context.Context, l LogFunc, msg string, keysAndValues []interface{}) (recovered interface{}) {
+	defer func() {
+		recovered = recover()
+	}()
+
+	l(ctx, msg, keysAndValues...)
+
+	return nil
+}
+
+func (m multiLogger) Enabled(ctx context.Context, level Level) bool {
+	for _, l := range m {
+		if Enabled(ctx, l, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m multiLogger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	m.dispatch(ctx, msg, keysAndValues, func(l Logger) LogFunc { return l.Debug })
+}
+
+func (m multiLogger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	m.dispatch(ctx, msg, keysAndValues, func(l Logger) LogFunc { return l.Info })
+}
+
+func (m multiLogger) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	m.dispatch(ctx, msg, keysAndValues, func(l Logger) LogFunc { return l.Important })
+}
+
+func (m multiLogger) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	m.dispatch(ctx, msg, keysAndValues, func(l Logger) LogFunc { return l.Warn })
+}
+
+func (m multiLogger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	m.dispatch(ctx, msg, keysAndValues, func(l Logger) LogFunc { return l.Error })
+}
@@ -0,0 +1,49 @@
+package ctxd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+)
+
+type panickingLogger struct {
+	ctxd.NoOpLogger
+}
+
+func (panickingLogger) Warn(_ context.Context, msg string, _ ...interface{}) {
+	panic("failed to warn: " + msg)
+}
+
+func TestMultiLogger(t *testing.T) {
+	all := ctxd.LoggerMock{}
+	warn := ctxd.LoggerMock{}
+
+	l := ctxd.MultiLogger(
+		&all,
+		panickingLogger{},
+		ctxd.LoggerWithLevel(&warn, ctxd.NewAtomicLevel(ctxd.WarnLevel)),
+	)
+	ctx := ctxd.AddFields(context.Background(), "foo", 1)
+
+	l.Debug(ctx, "debug")
+	l.Info(ctx, "info")
+	l.Important(ctx, "important")
+	l.Warn(ctx, "warn")
+	l.Error(ctx, "error")
+
+	assert.Equal(t, `debug: debug {"foo":1}
+info: info {"foo":1}
+important: important {"foo":1}
+warn: warn {"foo":1}
+error: logger panicked {"foo":1,"log.message":"warn","logger.index":1,"panic":"failed to warn: warn"}
+error: error {"foo":1}
+`, all.String())
+
+	assert.Equal(t, `important: important {"foo":1}
+warn: warn {"foo":1}
+error: logger panicked {"foo":1,"log.message":"warn","logger.index":1,"panic":"failed to warn: warn"}
+error: error {"foo":1}
+`, warn.String())
+}
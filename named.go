@@ -0,0 +1,180 @@
+package ctxd
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelRegistry is a concurrency-safe registry of levels of named loggers.
+//
+// Level of a name is inherited by names with dot-separated suffix,
+// e.g. level of "db" applies to "db.pool" unless "db.pool" has its own level.
+// Level of empty name applies to all names.
+//
+// Zero value is an empty registry.
+type LevelRegistry struct {
+	mu     sync.Mutex
+	levels atomic.Value // map[string]Level, replaced on update.
+}
+
+// NewLevelRegistry creates an empty level registry.
+func NewLevelRegistry() *LevelRegistry {
+	r := LevelRegistry{}
+	r.levels.Store(map[string]Level{})
+
+	return &r
+}
+
+// DefaultLevelRegistry is used by Named.
+var DefaultLevelRegistry = NewLevelRegistry()
+
+// SetLevel sets level of name and names inheriting it.
+func (r *LevelRegistry) SetLevel(name string, level Level) {
+	r.update(func(levels map[string]Level) {
+		levels[name] = level
+	})
+}
+
+// UnsetLevel removes level of name, so that it inherits level of parent name.
+func (r *LevelRegistry) UnsetLevel(name string) {
+	r.update(func(levels map[string]Level) {
+		delete(levels, name)
+	})
+}
+
+// Levels returns a copy of configured levels.
+func (r *LevelRegistry) Levels() map[string]Level {
+	levels := r.load()
+	res := make(map[string]Level, len(levels))
+
+	for k, v := range levels {
+		res[k] = v
+	}
+
+	return res
+}
+
+// Level returns level of name, it is inherited from the closest parent name if not set for name.
+//
+// False is returned if neither name nor any of its parents have level.
+func (r *LevelRegistry) Level(name string) (Level, bool) {
+	levels := r.load()
+	if len(levels) == 0 {
+		return 0, false
+	}
+
+	for {
+		if l, ok := levels[name]; ok {
+			return l, true
+		}
+
+		if name == "" {
+			return 0, false
+		}
+
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			name = name[:i]
+		} else {
+			name = ""
+		}
+	}
+}
+
+// Named creates named logger that uses levels of registry, see Named.
+func (r *LevelRegistry) Named(logger Logger, name string) Logger {
+	if n, ok := logger.(*named); ok {
+		logger = n.logger
+
+		if n.name != "" && name != "" {
+			name = n.name + "." + name
+		} else if name == "" {
+			name = n.name
+		}
+	}
+
+	return &named{
+		logger:   logger,
+		name:     name,
+		registry: r,
+		fields:   []interface{}{defaultFieldNames.LoggerName, name},
+	}
+}
+
+func (r *LevelRegistry) load() map[string]Level {
+	levels, _ := r.levels.Load().(map[string]Level)
+
+	return levels
+}
+
+func (r *LevelRegistry) update(f func(levels map[string]Level)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	levels := r.Levels()
+	f(levels)
+	r.levels.Store(levels)
+}
+
+// Named instruments contextualized logger with a name of component.
+//
+// Name is added as "log.logger" field, names of nested named loggers are joined with dot,
+// e.g. Named(Named(logger, "db"), "pool") is named "db.pool".
+//
+// Messages below the level of name in DefaultLevelRegistry are discarded, Important messages are never discarded.
+// Level from context (see WithLevel, WithDebug) takes precedence over level of name.
+func Named(logger Logger, name string) Logger {
+	return DefaultLevelRegistry.Named(logger, name)
+}
+
+type named struct {
+	logger   Logger
+	name     string
+	registry *LevelRegistry
+	fields   []interface{}
+}
+
+func (n *named) levelEnabled(ctx context.Context, level Level) bool {
+	if minLevel, ok := n.registry.Level(n.name); ok {
+		return levelEnabled(ctx, level, minLevel)
+	}
+
+	return true
+}
+
+func (n *named) Enabled(ctx context.Context, level Level) bool {
+	if level != ImportantLevel && !n.levelEnabled(ctx, level) {
+		return false
+	}
+
+	return Enabled(ctx, n.logger, level)
+}
+
+func (n *named) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if n.levelEnabled(ctx, DebugLevel) {
+		n.logger.Debug(AddFields(ctx, n.fields...), msg, keysAndValues...)
+	}
+}
+
+func (n *named) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if n.levelEnabled(ctx, InfoLevel) {
+		n.logger.Info(AddFields(ctx, n.fields...), msg, keysAndValues...)
+	}
+}
+
+func (n *named) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	n.logger.Important(AddFields(ctx, n.fields...), msg, keysAndValues...)
+}
+
+func (n *named) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if n.levelEnabled(ctx, WarnLevel) {
+		n.logger.Warn(AddFields(ctx, n.fields...), msg, keysAndValues...)
+	}
+}
+
+func (n *named) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if n.levelEnabled(ctx, ErrorLevel) {
+		n.logger.Error(AddFields(ctx, n.fields...), msg, keysAndValues...)
+	}
+}
@@ -0,0 +1,91 @@
+package ctxd_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelRegistry_Level(t *testing.T) {
+	r := ctxd.NewLevelRegistry()
+
+	_, ok := r.Level("db.pool")
+	assert.False(t, ok)
+
+	r.SetLevel("db", ctxd.WarnLevel)
+	r.SetLevel("db.pool.conn", ctxd.DebugLevel)
+
+	l, ok := r.Level("db.pool")
+	assert.True(t, ok)
+	assert.Equal(t, ctxd.WarnLevel, l)
+
+	l, ok = r.Level("db.pool.conn.tx")
+	assert.True(t, ok)
+	assert.Equal(t, ctxd.DebugLevel, l)
+
+	_, ok = r.Level("dbx")
+	assert.False(t, ok)
+
+	r.SetLevel("", ctxd.ErrorLevel)
+
+	l, ok = r.Level("dbx")
+	assert.True(t, ok)
+	assert.Equal(t, ctxd.ErrorLevel, l)
+
+	r.UnsetLevel("db")
+
+	l, ok = r.Level("db.pool")
+	assert.True(t, ok)
+	assert.Equal(t, ctxd.ErrorLevel, l)
+
+	assert.Equal(t, map[string]ctxd.Level{"": ctxd.ErrorLevel, "db.pool.conn": ctxd.DebugLevel}, r.Levels())
+}
+
+func TestNamed(t *testing.T) {
+	r := ctxd.LevelRegistry{}
+	m := ctxd.LoggerMock{}
+	l := r.Named(r.Named(&m, "db"), "pool")
+	ctx := context.Background()
+
+	l.Debug(ctx, "debug")
+	l.Info(ctx, "info", "k", 1)
+
+	r.SetLevel("db", ctxd.WarnLevel)
+
+	l.Info(ctx, "info dropped")
+	l.Important(ctx, "important")
+	l.Warn(ctx, "warn")
+	l.Info(ctxd.WithDebug(ctx), "info with debug")
+
+	assert.False(t, ctxd.Enabled(ctx, l, ctxd.InfoLevel))
+	assert.True(t, ctxd.Enabled(ctx, l, ctxd.ImportantLevel))
+
+	r.SetLevel("db.pool", ctxd.ErrorLevel)
+
+	l.Warn(ctx, "warn dropped")
+	l.Error(ctx, "error")
+
+	assert.Equal(t, `debug: debug {"log.logger":"db.pool"}
+info: info {"k":1,"log.logger":"db.pool"}
+important: important {"log.logger":"db.pool"}
+warn: warn {"log.logger":"db.pool"}
+debug mode, info: info with debug {"log.logger":"db.pool"}
+error: error {"log.logger":"db.pool"}
+`, m.String())
+}
+
+func TestNamed_default(t *testing.T) {
+	m := ctxd.LoggerMock{}
+	l := ctxd.Named(&m, "named-test")
+
+	ctxd.DefaultLevelRegistry.SetLevel("named-test", ctxd.ErrorLevel)
+	defer ctxd.DefaultLevelRegistry.UnsetLevel("named-test")
+
+	l.Warn(context.Background(), "warn")
+	l.Error(context.Background(), "error")
+
+	assert.Equal(t, `error: error {"log.logger":"named-test"}
+`, m.String())
+}
@@ -7,6 +7,13 @@ type NoOpLogger struct{}
 
 var _ Logger = NoOpLogger{}
 
+var _ LevelEnabler = NoOpLogger{}
+
+// Enabled returns false as all messages are discarded.
+func (NoOpLogger) Enabled(_ context.Context, _ Level) bool {
+	return false
+}
+
 // Debug discards debug message.
 func (NoOpLogger) Debug(_ context.Context, _ string, _ ...interface{}) {}
 
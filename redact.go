@@ -0,0 +1,204 @@
+package ctxd
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue is a default replacement of sensitive values.
+const RedactedValue = "***"
+
+// Secret is a string value that is masked when formatted or serialized.
+//
+// Original value is available with string conversion, e.g. string(s).
+type Secret string
+
+// String implements fmt.Stringer.
+func (Secret) String() string {
+	return RedactedValue
+}
+
+// GoString implements fmt.GoStringer.
+func (Secret) GoString() string {
+	return RedactedValue
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (Secret) MarshalText() ([]byte, error) {
+	return []byte(RedactedValue), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + RedactedValue + `"`), nil
+}
+
+// Redactor replaces sensitive values in loosely-typed key-value pairs.
+type Redactor struct {
+	// Keys are case-insensitive names of sensitive fields, e.g. "password".
+	Keys []string
+
+	// KeyPatterns match names of sensitive fields, e.g. regexp.MustCompile(`(?i)token`).
+	KeyPatterns []*regexp.Regexp
+
+	// Types are types of sensitive values, e.g. reflect.TypeOf(Credentials{}).
+	Types []reflect.Type
+
+	// Replacement is a value to use instead of sensitive value, default RedactedValue.
+	Replacement interface{}
+}
+
+func (r *Redactor) sensitive(key string, value interface{}) bool {
+	if _, ok := value.(Secret); ok {
+		return false // Already masked.
+	}
+
+	for _, k := range r.Keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	for _, p := range r.KeyPatterns {
+		if p.MatchString(key) {
+			return true
+		}
+	}
+
+	if len(r.Types) > 0 && value != nil {
+		t := reflect.TypeOf(value)
+
+		for _, rt := range r.Types {
+			if rt == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r *Redactor) replacement() interface{} {
+	if r.Replacement != nil {
+		return r.Replacement
+	}
+
+	return RedactedValue
+}
+
+// Redact returns key-value pairs with sensitive values replaced.
+//
+// Original slice is returned if there are no sensitive values, otherwise a copy is made.
+func (r *Redactor) Redact(keysAndValues []interface{}) []interface{} {
+	res, _ := r.redact(keysAndValues)
+
+	return res
+}
+
+func (r *Redactor) redact(keysAndValues []interface{}) ([]interface{}, bool) {
+	var res []interface{}
+
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok || key == "" {
+			// Pairing is lost in malformed tail, so that every element is checked as a value of preceding one.
+			for j := i; j < len(keysAndValues); j++ {
+				key = ""
+				if j > i {
+					key, _ = keysAndValues[j-1].(string)
+				}
+
+				if r.sensitive(key, keysAndValues[j]) {
+					res = r.replace(res, keysAndValues, j)
+				}
+			}
+
+			break
+		}
+
+		if r.sensitive(key, keysAndValues[i+1]) {
+			res = r.replace(res, keysAndValues, i+1)
+		}
+	}
+
+	if res == nil {
+		return keysAndValues, false
+	}
+
+	return res, true
+}
+
+// replace sets replacement at position i of res, res is copied from keysAndValues if nil.
+func (r *Redactor) replace(res, keysAndValues []interface{}, i int) []interface{} {
+	if res == nil {
+		res = make([]interface{}, len(keysAndValues))
+		copy(res, keysAndValues)
+	}
+
+	res[i] = r.replacement()
+
+	return res
+}
+
+// RedactContext returns context with sensitive fields replaced.
+func (r *Redactor) RedactContext(ctx context.Context) context.Context {
+	redacted, changed := r.redact(Fields(ctx))
+	if !changed {
+		return ctx
+	}
+
+	return context.WithValue(ctx, fieldsCtxKey{}, redacted)
+}
+
+// LogFunc instruments log function with redaction of context fields and key-value pairs.
+//
+// It can be used with LogError to redact tuples of structured error, e.g.
+//
+//	ctxd.LogError(ctx, err, redactor.LogFunc(logger.Error))
+func (r *Redactor) LogFunc(l LogFunc) LogFunc {
+	return func(ctx context.Context, msg string, keysAndValues ...interface{}) {
+		l(r.RedactContext(ctx), msg, r.Redact(keysAndValues)...)
+	}
+}
+
+// LoggerWithRedaction instruments contextualized logger with redaction of sensitive values.
+//
+// Both context fields and call-site key-value pairs are redacted, including
+// tuples of structured errors logged with LogError using methods of this logger.
+func LoggerWithRedaction(logger Logger, r *Redactor) Logger {
+	return &withRedaction{
+		logger:   logger,
+		redactor: r,
+	}
+}
+
+type withRedaction struct {
+	logger   Logger
+	redactor *Redactor
+}
+
+func (w *withRedaction) Enabled(ctx context.Context, level Level) bool {
+	return Enabled(ctx, w.logger, level)
+}
+
+func (w *withRedaction) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Debug(w.redactor.RedactContext(ctx), msg, w.redactor.Redact(keysAndValues)...)
+}
+
+func (w *withRedaction) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Info(w.redactor.RedactContext(ctx), msg, w.redactor.Redact(keysAndValues)...)
+}
+
+func (w *withRedaction) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Important(w.redactor.RedactContext(ctx), msg, w.redactor.Redact(keysAndValues)...)
+}
+
+func (w *withRedaction) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Warn(w.redactor.RedactContext(ctx), msg, w.redactor.Redact(keysAndValues)...)
+}
+
+func (w *withRedaction) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	w.logger.Error(w.redactor.RedactContext(ctx), msg, w.redactor.Redact(keysAndValues)...)
+}
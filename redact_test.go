@@ -0,0 +1,92 @@
+package ctxd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type creditCard struct {
+	Number string
+}
+
+func TestSecret(t *testing.T) {
+	s := ctxd.Secret("pa$$w0rd")
+
+	assert.Equal(t, "pa$$w0rd", string(s))
+	assert.Equal(t, "*** *** ***", fmt.Sprintf("%v %s %#v", s, s, s))
+
+	j, err := json.Marshal(map[string]interface{}{"password": s})
+	require.NoError(t, err)
+	assert.Equal(t, `{"password":"***"}`, string(j))
+
+	err = ctxd.NewError(context.Background(), "failed", "password", s)
+	assert.Equal(t, "failed, password: ***", err.(fmt.Stringer).String())
+}
+
+func TestLoggerWithRedaction(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	r := &ctxd.Redactor{
+		Keys:        []string{"password"},
+		KeyPatterns: []*regexp.Regexp{regexp.MustCompile(`(?i)token`)},
+		Types:       []reflect.Type{reflect.TypeOf(creditCard{})},
+	}
+	l := ctxd.LoggerWithRedaction(&lm, r)
+
+	ctx := ctxd.AddFields(context.Background(), "accessToken", "abc", "user", "john")
+
+	l.Debug(ctx, "debug", "Password", "123")
+	l.Info(ctx, "info", "card", creditCard{Number: "4111"})
+	l.Important(ctx, "important", "secret", ctxd.Secret("s"))
+	l.Warn(context.Background(), "warn", "ok", 1)
+
+	err := ctxd.NewError(ctx, "failed", "password", "123")
+	ctxd.LogError(ctx, err, l.Error)
+
+	assert.Equal(t, `debug: debug {"Password":"***","accessToken":"***","user":"john"}
+info: info {"accessToken":"***","card":"***","user":"john"}
+important: important {"accessToken":"***","secret":"***","user":"john"}
+warn: warn {"ok":1}
+error: failed {"accessToken":"***","password":"***","user":"john"}
+`, lm.String())
+
+	// Original context is not affected.
+	assert.Equal(t, []interface{}{"accessToken", "abc", "user", "john"}, ctxd.Fields(ctx))
+}
+
+func TestRedactor_LogFunc(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	r := &ctxd.Redactor{Keys: []string{"password"}, Replacement: "[redacted]"}
+
+	err := ctxd.NewError(context.Background(), "failed", "password", "123", "user", "john")
+	ctxd.LogError(context.Background(), err, r.LogFunc(lm.Error))
+
+	assert.Equal(t, `error: failed {"password":"[redacted]","user":"john"}
+`, lm.String())
+
+	kv := []interface{}{"user", "john", 123, "malformed"}
+	assert.Equal(t, kv, r.Redact(kv))
+}
+
+func TestRedactor_Redact_malformed(t *testing.T) {
+	type credentials struct{ Token string }
+
+	r := &ctxd.Redactor{Keys: []string{"password"}, Types: []reflect.Type{reflect.TypeOf(credentials{})}}
+	kv := []interface{}{"password", "123", 42, "user", "password", "456", credentials{}, "", "password", "789"}
+
+	// Sensitive values after non-string key are redacted too.
+	assert.Equal(t, []interface{}{"password", "***", 42, "user", "password", "***", "***", "", "password", "***"},
+		r.Redact(kv))
+	assert.Equal(t, []interface{}{"password", "***", "", "password", "***"},
+		r.Redact([]interface{}{"password", "123", "", "password", "456"}))
+
+	// Original slice is not affected.
+	assert.Equal(t, "123", kv[1])
+}
@@ -0,0 +1,249 @@
+package ctxd
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SamplingConfig defines sampling of repetitive log entries.
+type SamplingConfig struct {
+	// Interval is a period of counting entries, default 1s.
+	Interval time.Duration
+
+	// First is a number of entries with the same level and message to log during interval, default 100.
+	First int
+
+	// Thereafter defines every Mth entry to log after First during interval, other entries are dropped.
+	// Zero value drops all entries after First.
+	Thereafter int
+
+	// SampleImportant enables sampling of Important messages.
+	SampleImportant bool
+
+	// SampleErrors enables sampling of Error messages.
+	SampleErrors bool
+
+	// ReportInterval is a minimal period between summaries of dropped entries, default 1m.
+	// Summary is logged with Warn level by a background goroutine or on the next call after interval passes.
+	ReportInterval time.Duration
+
+	// TimeNow returns current time, time.Now is used if nil.
+	TimeNow func() time.Time
+}
+
+// SamplingSummaryMessage is a message of summary entry that reports counts of dropped entries.
+const SamplingSummaryMessage = "log entries dropped by sampling"
+
+// LoggerWithSampling instruments contextualized logger with sampling of repetitive entries.
+//
+// Entries are counted by level and message, fields are not taken into account.
+// Counters are stored in a fixed number of buckets, so different messages may rarely share a counter.
+//
+// Close should be called to report pending dropped entries and stop background goroutine.
+func LoggerWithSampling(logger Logger, cfg SamplingConfig) *SamplingLogger {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+
+	if cfg.First <= 0 {
+		cfg.First = 100
+	}
+
+	if cfg.ReportInterval <= 0 {
+		cfg.ReportInterval = time.Minute
+	}
+
+	s := &SamplingLogger{
+		logger: logger,
+		cfg:    cfg,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	s.nextReport = timeNow(cfg.TimeNow).Add(cfg.ReportInterval).UnixNano()
+
+	go s.run()
+
+	return s
+}
+
+const samplingBuckets = 1024
+
+const (
+	samplingDebug = iota
+	samplingInfo
+	samplingImportant
+	samplingWarn
+	samplingError
+	samplingLevels
+)
+
+var samplingLevelNames = [samplingLevels]string{
+	"dropped.debug", "dropped.info", "dropped.important", "dropped.warn", "dropped.error",
+}
+
+// SamplingLogger drops repetitive entries and reports counts of dropped entries.
+type SamplingLogger struct {
+	// Counters are placed first to keep 64-bit alignment for atomic operations.
+	counters   [samplingLevels][samplingBuckets]samplingCounter
+	dropped    [samplingLevels]uint64
+	nextReport int64
+
+	logger Logger
+	cfg    SamplingConfig
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ Logger = &SamplingLogger{}
+
+type samplingCounter struct {
+	resetAt int64
+	count   uint64
+}
+
+func (c *samplingCounter) inc(now int64, interval time.Duration) uint64 {
+	resetAt := atomic.LoadInt64(&c.resetAt)
+	if resetAt > now {
+		return atomic.AddUint64(&c.count, 1)
+	}
+
+	atomic.StoreUint64(&c.count, 1)
+
+	if !atomic.CompareAndSwapInt64(&c.resetAt, resetAt, now+int64(interval)) {
+		return atomic.AddUint64(&c.count, 1)
+	}
+
+	return 1
+}
+
+// run reports dropped entries periodically, so that they are not held back when logging goes quiet.
+func (s *SamplingLogger) run() {
+	defer close(s.done)
+
+	t := time.NewTicker(s.cfg.ReportInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			s.report(timeNow(s.cfg.TimeNow).UnixNano())
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// allow checks if an entry should be logged and counts dropped entries.
+func (s *SamplingLogger) allow(level int, msg string) bool {
+	now := timeNow(s.cfg.TimeNow).UnixNano()
+	s.report(now)
+
+	// FNV-1a hash of message.
+	h := uint32(2166136261)
+
+	for i := 0; i < len(msg); i++ {
+		h ^= uint32(msg[i])
+		h *= 16777619
+	}
+
+	n := s.counters[level][h%samplingBuckets].inc(now, s.cfg.Interval)
+	first := uint64(s.cfg.First)
+
+	if n <= first || (s.cfg.Thereafter > 0 && (n-first)%uint64(s.cfg.Thereafter) == 0) {
+		return true
+	}
+
+	atomic.AddUint64(&s.dropped[level], 1)
+
+	return false
+}
+
+// report logs summary of dropped entries if report interval has passed.
+func (s *SamplingLogger) report(now int64) {
+	next := atomic.LoadInt64(&s.nextReport)
+	if next > now || !atomic.CompareAndSwapInt64(&s.nextReport, next, now+int64(s.cfg.ReportInterval)) {
+		return
+	}
+
+	s.reportDropped()
+}
+
+// reportDropped logs summary of dropped entries and resets their counts.
+func (s *SamplingLogger) reportDropped() {
+	var kv []interface{}
+
+	for i := range s.dropped {
+		if n := atomic.SwapUint64(&s.dropped[i], 0); n > 0 {
+			kv = append(kv, samplingLevelNames[i], n)
+		}
+	}
+
+	if len(kv) > 0 {
+		s.logger.Warn(context.Background(), SamplingSummaryMessage, kv...)
+	}
+}
+
+// Close stops background goroutine and reports pending dropped entries.
+//
+// Entries logged after Close are still sampled, their drops are reported on the next call after ReportInterval.
+// Close returns ctx error if background goroutine does not stop before ctx is done.
+func (s *SamplingLogger) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+	})
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	s.reportDropped()
+
+	return nil
+}
+
+// Enabled checks if underlying logger is enabled for level in context.
+func (s *SamplingLogger) Enabled(ctx context.Context, level Level) bool {
+	return Enabled(ctx, s.logger, level)
+}
+
+// Debug logs a message if it is not dropped by sampling.
+func (s *SamplingLogger) Debug(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if s.allow(samplingDebug, msg) {
+		s.logger.Debug(ctx, msg, keysAndValues...)
+	}
+}
+
+// Info logs a message if it is not dropped by sampling.
+func (s *SamplingLogger) Info(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if s.allow(samplingInfo, msg) {
+		s.logger.Info(ctx, msg, keysAndValues...)
+	}
+}
+
+// Important logs a message if it is not dropped by sampling.
+func (s *SamplingLogger) Important(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if !s.cfg.SampleImportant || s.allow(samplingImportant, msg) {
+		s.logger.Important(ctx, msg, keysAndValues...)
+	}
+}
+
+// Warn logs a message if it is not dropped by sampling.
+func (s *SamplingLogger) Warn(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if s.allow(samplingWarn, msg) {
+		s.logger.Warn(ctx, msg, keysAndValues...)
+	}
+}
+
+// Error logs a message if it is not dropped by sampling.
+func (s *SamplingLogger) Error(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	if !s.cfg.SampleErrors || s.allow(samplingError, msg) {
+		s.logger.Error(ctx, msg, keysAndValues...)
+	}
+}
@@ -0,0 +1,199 @@
+package ctxd_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerWithSampling(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithSampling(&lm, ctxd.SamplingConfig{
+		Interval:       time.Hour,
+		First:          2,
+		Thereafter:     3,
+		ReportInterval: time.Hour,
+	})
+	ctx := context.Background()
+
+	defer func() {
+		assert.NoError(t, l.Close(ctx))
+	}()
+
+	for i := 1; i <= 10; i++ {
+		l.Debug(ctx, "debug", "i", i)
+		l.Info(ctx, "info", "i", i)
+		l.Important(ctx, "important", "i", i)
+		l.Warn(ctx, "warn", "i", i)
+		l.Error(ctx, "error", "i", i)
+	}
+
+	count := map[string][]interface{}{}
+
+	for _, e := range lm.LoggedEntries {
+		count[e.Message] = append(count[e.Message], e.Data["i"])
+	}
+
+	assert.Equal(t, map[string][]interface{}{
+		"debug":     {1, 2, 5, 8},
+		"info":      {1, 2, 5, 8},
+		"important": {1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		"warn":      {1, 2, 5, 8},
+		"error":     {1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+	}, count)
+}
+
+func TestLoggerWithSampling_report(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithSampling(&lm, ctxd.SamplingConfig{
+		Interval:        time.Hour,
+		First:           1,
+		SampleImportant: true,
+		SampleErrors:    true,
+		ReportInterval:  time.Second,
+		TimeNow:         fakeClock(), // Clock advances by report interval on each call.
+	})
+	ctx := context.Background()
+
+	defer func() {
+		assert.NoError(t, l.Close(ctx))
+	}()
+
+	l.Info(ctx, "info")
+	l.Info(ctx, "info")
+	l.Important(ctx, "important")
+	l.Important(ctx, "important")
+	l.Error(ctx, "error")
+	l.Error(ctx, "error")
+	l.Error(ctx, "error")
+	l.Info(ctx, "another info")
+
+	// Summary is logged on the next call after drop.
+	assert.Equal(t, `info: info null
+warn: log entries dropped by sampling {"dropped.info":1}
+important: important null
+warn: log entries dropped by sampling {"dropped.important":1}
+error: error null
+warn: log entries dropped by sampling {"dropped.error":1}
+warn: log entries dropped by sampling {"dropped.error":1}
+info: another info null
+`, lm.String())
+}
+
+func TestLoggerWithSampling_report_quiet(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithSampling(&lm, ctxd.SamplingConfig{
+		Interval:       time.Hour,
+		First:          1,
+		ReportInterval: 10 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	l.Info(ctx, "info")
+	l.Info(ctx, "info")
+
+	// Summary is logged by background goroutine without further calls.
+	assert.Eventually(t, func() bool {
+		return len(lm.Entries()) == 2
+	}, time.Second, time.Millisecond)
+
+	assert.NoError(t, l.Close(ctx))
+	assert.Equal(t, `info: info null
+warn: log entries dropped by sampling {"dropped.info":1}
+`, lm.String())
+}
+
+func TestSamplingLogger_Close(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithSampling(&lm, ctxd.SamplingConfig{
+		Interval:       time.Hour,
+		First:          1,
+		ReportInterval: time.Hour,
+	})
+	ctx := context.Background()
+
+	l.Info(ctx, "info")
+	l.Info(ctx, "info")
+	l.Warn(ctx, "warn")
+	l.Warn(ctx, "warn")
+	l.Warn(ctx, "warn")
+
+	assert.Equal(t, "info: info null\nwarn: warn null\n", lm.String())
+
+	// Pending counts are reported on Close.
+	assert.NoError(t, l.Close(ctx))
+	assert.NoError(t, l.Close(ctx))
+
+	assert.Equal(t, `info: info null
+warn: warn null
+warn: log entries dropped by sampling {"dropped.info":1,"dropped.warn":2}
+`, lm.String())
+}
+
+func TestLoggerWithSampling_concurrent(t *testing.T) {
+	l := ctxd.LoggerWithSampling(ctxd.NoOpLogger{}, ctxd.SamplingConfig{
+		Interval:       time.Millisecond,
+		ReportInterval: time.Millisecond,
+	})
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				l.Info(context.Background(), "hello "+strconv.Itoa(i%3))
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	assert.NoError(t, l.Close(context.Background()))
+}
+
+func BenchmarkLoggerWithSampling(b *testing.B) {
+	l := ctxd.LoggerWithSampling(ctxd.NoOpLogger{}, ctxd.SamplingConfig{})
+	ctx := context.Background()
+
+	defer func() {
+		_ = l.Close(ctx)
+	}()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		l.Info(ctx, "hello", "i", i)
+	}
+}
+
+func TestLoggerWithSampling_TimeNow(t *testing.T) {
+	lm := ctxd.LoggerMock{}
+	l := ctxd.LoggerWithSampling(&lm, ctxd.SamplingConfig{
+		Interval:       1500 * time.Millisecond,
+		First:          1,
+		ReportInterval: time.Hour,
+		TimeNow:        fakeClock(),
+	})
+	ctx := context.Background()
+
+	defer func() {
+		assert.NoError(t, l.Close(ctx))
+	}()
+
+	// Clock advances by a second on each call, so that counter is reset on every second call.
+	l.Info(ctx, "info 1")
+	l.Info(ctx, "info 1")
+	l.Info(ctx, "info 1")
+	l.Info(ctx, "info 1")
+
+	assert.Equal(t, `info: info 1 null
+info: info 1 null
+`, lm.String())
+}
@@ -0,0 +1,169 @@
+package ctxd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// StackTrace is a call stack captured with error.
+type StackTrace []uintptr
+
+// String returns formatted call stack with function and file:line for each frame.
+func (st StackTrace) String() string {
+	if len(st) == 0 {
+		return ""
+	}
+
+	sb := strings.Builder{}
+	frames := runtime.CallersFrames(st)
+
+	for {
+		f, more := frames.Next()
+
+		sb.WriteString(f.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(f.File)
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(f.Line))
+		sb.WriteString("\n")
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
+
+// StackTracer defines error with captured call stack.
+type StackTracer interface {
+	StackTrace() StackTrace
+}
+
+var errorStackEnabled int32
+
+// CaptureErrorStack enables or disables capturing of call stack in NewError and WrapError.
+//
+// Call stack is not captured when wrapped error already has it.
+// Use WithStack to capture call stack for a particular error.
+func CaptureErrorStack(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&errorStackEnabled, v)
+}
+
+// WithStack returns error annotated with call stack, unless error already has it.
+//
+// If err is nil, WithStack returns nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return withStack(err)
+}
+
+func withStack(err error) error {
+	if hasStack(err) {
+		return err
+	}
+
+	return stackError{
+		err:   err,
+		stack: captureStack(),
+	}
+}
+
+// maybeWithStack annotates error with call stack if capturing is enabled globally.
+func maybeWithStack(err error) error {
+	if atomic.LoadInt32(&errorStackEnabled) == 0 {
+		return err
+	}
+
+	return withStack(err)
+}
+
+func hasStack(err error) bool {
+	var st StackTracer
+
+	return errors.As(err, &st)
+}
+
+// captureStack returns call stack without frames of ctxd package.
+func captureStack() StackTrace {
+	var pcs [64]uintptr
+
+	// Skip runtime.Callers and captureStack.
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	skip := 0
+
+	for {
+		f, more := frames.Next()
+		if !inPackage(f.Function, ctxdPackage) || !more {
+			break
+		}
+
+		skip++
+	}
+
+	st := make(StackTrace, n-skip)
+	copy(st, pcs[skip:n])
+
+	return st
+}
+
+type stackError struct {
+	err   error
+	stack StackTrace
+}
+
+// Error returns message.
+func (se stackError) Error() string {
+	return se.err.Error()
+}
+
+// Unwrap returns original error.
+func (se stackError) Unwrap() error {
+	return se.err
+}
+
+// StackTrace returns captured call stack.
+func (se stackError) StackTrace() StackTrace {
+	return se.stack
+}
+
+// Format implements fmt.Formatter, %+v prints message with call stack.
+func (se stackError) Format(s fmt.State, verb rune) {
+	formatError(se, s, verb)
+}
+
+// formatError prints error message, and also call stack for %+v.
+func formatError(err error, s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		_, _ = io.WriteString(s, err.Error())
+
+		if s.Flag('+') {
+			var st StackTracer
+
+			if errors.As(err, &st) {
+				_, _ = io.WriteString(s, "\n"+st.StackTrace().String())
+			}
+		}
+	case 's':
+		_, _ = io.WriteString(s, err.Error())
+	case 'q':
+		_, _ = io.WriteString(s, strconv.Quote(err.Error()))
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(%s)", verb, err.Error())
+	}
+}
@@ -0,0 +1,82 @@
+package ctxd_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureErrorStack(t *testing.T) {
+	ctxd.CaptureErrorStack(true)
+	defer ctxd.CaptureErrorStack(false)
+
+	ctx := ctxd.AddFields(context.Background(), "foo", 1)
+
+	err := ctxd.NewError(ctx, "failed")
+
+	var st ctxd.StackTracer
+
+	require.True(t, errors.As(err, &st))
+
+	stack := st.StackTrace().String()
+	assert.True(t, strings.HasPrefix(stack, "github.com/bool64/ctxd_test.TestCaptureErrorStack\n\t"), stack)
+	assert.Contains(t, stack, "/stack_test.go:")
+
+	// Errors of NewError are not unwrapped.
+	assert.Nil(t, errors.Unwrap(err))
+	assert.Nil(t, errors.Unwrap(ctxd.NewError(context.Background(), "failed")))
+	assert.Equal(t, "failed\n", fmt.Sprintf("%+v", ctxd.NewError(context.Background(), "failed"))[:7])
+
+	// Stack is not captured again when wrapping.
+	wrapped := ctxd.WrapError(ctx, err, "wrapped")
+	assert.Equal(t, err, errors.Unwrap(errors.Unwrap(wrapped)))
+
+	require.True(t, errors.As(wrapped, &st))
+	assert.Equal(t, stack, st.StackTrace().String())
+
+	assert.Equal(t, "wrapped: failed", fmt.Sprintf("%v", wrapped))
+	assert.Equal(t, "wrapped: failed", fmt.Sprintf("%s", wrapped))
+	assert.Equal(t, `"wrapped: failed"`, fmt.Sprintf("%q", wrapped))
+	assert.Equal(t, "wrapped: failed\n"+stack, fmt.Sprintf("%+v", wrapped))
+
+	lm := ctxd.LoggerMock{}
+	ctxd.LogError(ctx, wrapped, lm.Error)
+
+	require.Len(t, lm.LoggedEntries, 1)
+	assert.Equal(t, map[string]interface{}{"foo": 1, "error.stack_trace": stack}, lm.LoggedEntries[0].Data)
+}
+
+func TestWithStack(t *testing.T) {
+	assert.Nil(t, ctxd.WithStack(nil))
+
+	err := ctxd.WithStack(errors.New("failed"))
+	assert.Equal(t, err, ctxd.WithStack(err))
+
+	var st ctxd.StackTracer
+
+	require.True(t, errors.As(err, &st))
+
+	stack := st.StackTrace().String()
+	assert.True(t, strings.HasPrefix(stack, "github.com/bool64/ctxd_test.TestWithStack\n\t"), stack)
+	assert.Equal(t, "failed\n"+stack, fmt.Sprintf("%+v", err))
+	assert.Equal(t, "%!d(failed)", fmt.Sprintf("%d", err))
+
+	lm := ctxd.LoggerMock{}
+	ctxd.LogError(context.Background(), err, lm.Error)
+
+	require.Len(t, lm.LoggedEntries, 1)
+	assert.Equal(t, map[string]interface{}{"error.stack_trace": stack}, lm.LoggedEntries[0].Data)
+
+	// Stack is not captured by default.
+	err = ctxd.NewError(context.Background(), "failed", "foo", 1)
+	assert.False(t, errors.As(err, &st))
+	assert.Nil(t, errors.Unwrap(err))
+	assert.Equal(t, "failed", fmt.Sprintf("%+v", err))
+	assert.Equal(t, "", ctxd.StackTrace(nil).String())
+}